@@ -1,7 +1,11 @@
 module github.com/terraform-providers/terraform-provider-grafana
 
 require (
+	github.com/hashicorp/go-cleanhttp v0.5.1
+	github.com/hashicorp/go-version v1.1.0
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/hashicorp/terraform v0.12.2
 	github.com/nytm/go-grafana-api v0.2.0
+	github.com/zclconf/go-cty v0.0.0-20190516203816-4fecf87372ec
+	github.com/zclconf/go-cty-yaml v0.1.0
 )
@@ -0,0 +1,148 @@
+package grafana
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	gapi "github.com/nytm/go-grafana-api"
+)
+
+// client is the meta value handed to every resource. Besides the API client
+// it holds state shared between all resources of one provider instance.
+type client struct {
+	gapi          *gapi.Client
+	baseURL       url.URL
+	auth          string
+	users         *userCache
+	orgUsers      *orgUserCache
+	instanceCache instanceCache
+
+	// minRefreshInterval is the shortest refresh interval dashboards may
+	// have, 0 meaning any.
+	minRefreshInterval time.Duration
+
+	defaultDataSource defaultDataSourceClaim
+}
+
+func newClient(auth, baseURL string) (*client, error) {
+	gc, err := gapi.New(auth, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return &client{
+		gapi:     gc,
+		baseURL:  *u,
+		auth:     auth,
+		users:    newUserCache(userCacheTTL),
+		orgUsers: newOrgUserCache(userCacheTTL),
+	}, nil
+}
+
+// orgTransport makes every request in the organization orgId, unless the
+// request picks an organization itself.
+type orgTransport struct {
+	base  http.RoundTripper
+	orgId int64
+}
+
+func (t *orgTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("X-Grafana-Org-Id") == "" {
+		// RoundTrippers mustn't modify the request, so set the header on a
+		// copy.
+		r2 := new(http.Request)
+		*r2 = *req
+		r2.Header = make(http.Header, len(req.Header)+1)
+		for k, v := range req.Header {
+			r2.Header[k] = append([]string(nil), v...)
+		}
+		req = r2
+		req.Header.Set("X-Grafana-Org-Id", strconv.FormatInt(t.orgId, 10))
+	}
+	return t.base.RoundTrip(req)
+}
+
+// apiError is a non-200 response of the Grafana API. Its message is only the
+// response status, e.g. "404 Not Found", so that it compares like the errors
+// of go-grafana-api. The body is kept for endpoints explaining failures in it.
+type apiError struct {
+	status string
+	body   []byte
+}
+
+func (e *apiError) Error() string {
+	return e.status
+}
+
+// request calls an endpoint of the Grafana API that go-grafana-api doesn't
+// cover. body and responseStruct are encoded and decoded as JSON when not nil.
+// A non-200 response is returned as an *apiError.
+func (c *client) request(method, requestPath string, query url.Values, body, responseStruct interface{}) error {
+	return c.requestWithHeader(method, requestPath, query, nil, body, responseStruct)
+}
+
+// orgRequest is request made in the organization orgId rather than the
+// current organization of the authenticated user. Grafana only allows this
+// with basic auth, API keys belong to a single organization.
+func (c *client) orgRequest(orgId int64, method, requestPath string, query url.Values, body, responseStruct interface{}) error {
+	header := http.Header{}
+	header.Set("X-Grafana-Org-Id", strconv.FormatInt(orgId, 10))
+	return c.requestWithHeader(method, requestPath, query, header, body, responseStruct)
+}
+
+func (c *client) requestWithHeader(method, requestPath string, query url.Values, header http.Header, body, responseStruct interface{}) error {
+	u := c.baseURL
+	u.Path = path.Join(u.Path, requestPath)
+	u.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u.String(), reader)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(c.auth, ":") {
+		split := strings.SplitN(c.auth, ":", 2)
+		req.SetBasicAuth(split[0], split[1])
+	} else {
+		req.Header.Add("Authorization", "Bearer "+c.auth)
+	}
+	req.Header.Add("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := c.gapi.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != 200 {
+		return &apiError{status: resp.Status, body: data}
+	}
+	if responseStruct == nil {
+		return nil
+	}
+	return json.Unmarshal(data, responseStruct)
+}
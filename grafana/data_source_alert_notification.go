@@ -0,0 +1,54 @@
+package grafana
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceAlertNotification() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadAlertNotificationData,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"uid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func ReadAlertNotificationData(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("name").(string)
+
+	// The settings may hold secrets and are deliberately left out. Grafana
+	// before 6.2 has no notification channel uids.
+	var notifications []struct {
+		Id   int64  `json:"id"`
+		Uid  string `json:"uid"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if err := meta.(*client).request("GET", "/api/alert-notifications/", nil, nil, &notifications); err != nil {
+		return err
+	}
+	for _, n := range notifications {
+		if n.Name == name {
+			d.SetId(strconv.FormatInt(n.Id, 10))
+			d.Set("uid", n.Uid)
+			d.Set("type", n.Type)
+			return nil
+		}
+	}
+	return fmt.Errorf("Error: A Grafana alert notification with the name '%s' does not exist.", name)
+}
@@ -0,0 +1,55 @@
+package grafana
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceAlertNotification_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAlertNotificationConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.grafana_alert_notification.test", "id", "grafana_alert_notification.test", "id",
+					),
+					resource.TestCheckResourceAttr(
+						"data.grafana_alert_notification.test", "type", "email",
+					),
+					resource.TestMatchResourceAttr(
+						"data.grafana_alert_notification.test", "uid", regexp.MustCompile(`\w+`),
+					),
+				),
+			},
+			{
+				Config:      testAccDataSourceAlertNotificationConfig_missing,
+				ExpectError: regexp.MustCompile("alert notification with the name 'terraform-acc-missing' does not exist"),
+			},
+		},
+	})
+}
+
+const testAccDataSourceAlertNotificationConfig_basic = `
+resource "grafana_alert_notification" "test" {
+    type = "email"
+    name = "terraform-acc-alert-notification-lookup"
+    settings = {
+        "addresses" = "foo@bar.test"
+    }
+}
+
+data "grafana_alert_notification" "test" {
+    name = "${grafana_alert_notification.test.name}"
+}
+`
+
+const testAccDataSourceAlertNotificationConfig_missing = `
+data "grafana_alert_notification" "missing" {
+    name = "terraform-acc-missing"
+}
+`
@@ -0,0 +1,157 @@
+package grafana
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// annotationsPageSize is the number of annotations requested at a time.
+const annotationsPageSize = 100
+
+type annotation struct {
+	Id   int64    `json:"id"`
+	Time int64    `json:"time"`
+	Text string   `json:"text"`
+	Tags []string `json:"tags"`
+}
+
+func DataSourceAnnotations() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadAnnotations,
+
+		Schema: map[string]*schema.Schema{
+			"dashboard_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"panel_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"from": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"to": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"annotations": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"text": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadAnnotations(d *schema.ResourceData, meta interface{}) error {
+	query := url.Values{}
+	if v, ok := d.GetOk("dashboard_id"); ok {
+		query.Set("dashboardId", strconv.Itoa(v.(int)))
+	}
+	if v, ok := d.GetOk("panel_id"); ok {
+		query.Set("panelId", strconv.Itoa(v.(int)))
+	}
+	if v, ok := d.GetOk("from"); ok {
+		query.Set("from", strconv.Itoa(v.(int)))
+	}
+	if v, ok := d.GetOk("to"); ok {
+		query.Set("to", strconv.Itoa(v.(int)))
+	}
+	for _, tag := range d.Get("tags").([]interface{}) {
+		query.Add("tags", tag.(string))
+	}
+
+	annotations, err := listAnnotations(meta.(*client), query)
+	if err != nil {
+		return err
+	}
+
+	result := make([]map[string]interface{}, 0, len(annotations))
+	for _, a := range annotations {
+		result = append(result, map[string]interface{}{
+			"id":   a.Id,
+			"text": a.Text,
+			"time": a.Time,
+			"tags": a.Tags,
+		})
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(query.Encode())))
+	d.Set("annotations", result)
+	return nil
+}
+
+// listAnnotations returns every annotation matching query. The annotations
+// API has no page parameter and returns the newest annotations first, so
+// pages are walked by moving the end of the time range back to the oldest
+// annotation seen so far. As the bound is inclusive, that annotation is
+// returned again and skipped. Grafana ignores the time range unless both of
+// its ends are set, so the start defaults to the epoch.
+func listAnnotations(c *client, query url.Values) ([]annotation, error) {
+	query.Set("limit", strconv.Itoa(annotationsPageSize))
+	if query.Get("from") == "" {
+		query.Set("from", "1")
+	}
+
+	var annotations []annotation
+	seen := make(map[int64]bool)
+	for {
+		var page []annotation
+		if err := c.request("GET", "/api/annotations", query, nil, &page); err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, a := range page {
+			if seen[a.Id] {
+				continue
+			}
+			seen[a.Id] = true
+			annotations = append(annotations, a)
+			added++
+		}
+		if len(page) < annotationsPageSize {
+			return annotations, nil
+		}
+		// A full page of already seen annotations means more than a page of
+		// them share a single timestamp, which the time cursor can't get past.
+		if added == 0 {
+			return nil, fmt.Errorf("Error: More than %d annotations share the time %d, narrow down the query to list them.", annotationsPageSize, page[len(page)-1].Time)
+		}
+		query.Set("to", strconv.FormatInt(page[len(page)-1].Time, 10))
+	}
+}
@@ -0,0 +1,91 @@
+package grafana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestListAnnotations_paginates(t *testing.T) {
+	// 250 annotations, newest first, with the last 3 sharing a timestamp so
+	// that a page boundary falls between annotations of the same time.
+	all := make([]annotation, 250)
+	for i := range all {
+		all[i] = annotation{Id: int64(i + 1), Time: int64(10000 - i), Text: "deploy"}
+	}
+	for i := 97; i < 100; i++ {
+		all[i].Time = all[97].Time
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/annotations" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("dashboardId"); got != "3" {
+			t.Errorf("expected dashboardId 3, got %q", got)
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		// Like Grafana, the time range only applies when both ends are set.
+		from, _ := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		if from <= 0 || to <= 0 {
+			from, to = 0, 1<<62
+		}
+		page := []annotation{}
+		for _, a := range all {
+			if a.Time >= from && a.Time <= to && len(page) < limit {
+				page = append(page, a)
+			}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations, err := listAnnotations(c, url.Values{"dashboardId": {"3"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(annotations) != len(all) {
+		t.Fatalf("expected %d annotations, got %d", len(all), len(annotations))
+	}
+	for i, a := range annotations {
+		if a.Id != all[i].Id {
+			t.Fatalf("expected annotation %d to have id %d, got %d", i, all[i].Id, a.Id)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestListAnnotations_sameTimeOverflow(t *testing.T) {
+	// More annotations share one timestamp than fit in a page.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page := make([]annotation, limit)
+		for i := range page {
+			page[i] = annotation{Id: int64(i + 1), Time: 5000, Text: "deploy"}
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = listAnnotations(c, url.Values{})
+	if err == nil || !strings.Contains(err.Error(), "share the time 5000") {
+		t.Fatalf("expected an error rather than a partial list, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceDashboard() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadDashboardData,
+
+		Schema: map[string]*schema.Schema{
+			"slug": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"include_panels": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"title": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"folder": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"config_json": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"panels": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadDashboardData(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*client).gapi
+	slug := d.Get("slug").(string)
+	dashboard, err := client.Dashboard(slug)
+	if err != nil {
+		if err.Error() == "404 Not Found" {
+			return fmt.Errorf("Error: A Grafana dashboard with the slug '%s' does not exist.", slug)
+		}
+		return err
+	}
+
+	configJSON, err := json.Marshal(dashboard.Model)
+	if err != nil {
+		return err
+	}
+
+	panels := []map[string]interface{}{}
+	if d.Get("include_panels").(bool) {
+		panels = dashboardPanels(dashboard.Model)
+	}
+
+	title, _ := dashboard.Model["title"].(string)
+
+	d.SetId(dashboard.Meta.Slug)
+	d.Set("title", title)
+	d.Set("folder", dashboard.Folder)
+	d.Set("config_json", NormalizeDashboardConfigJSON(string(configJSON)))
+	d.Set("panels", panels)
+	return nil
+}
+
+// dashboardPanels lists every panel of a dashboard model in display order.
+// Panels nested in rows, either in the "rows" of dashboards from before
+// Grafana 5.0 or inside collapsed row panels, are included while the rows
+// themselves are not.
+func dashboardPanels(model map[string]interface{}) []map[string]interface{} {
+	panels := []map[string]interface{}{}
+	var collect func(list interface{})
+	collect = func(list interface{}) {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			panel, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if panel["type"] == "row" {
+				collect(panel["panels"])
+				continue
+			}
+			id, _ := panel["id"].(float64)
+			title, _ := panel["title"].(string)
+			panelType, _ := panel["type"].(string)
+			panels = append(panels, map[string]interface{}{
+				"id":    int(id),
+				"title": title,
+				"type":  panelType,
+			})
+		}
+	}
+
+	rows, _ := model["rows"].([]interface{})
+	for _, row := range rows {
+		if row, ok := row.(map[string]interface{}); ok {
+			collect(row["panels"])
+		}
+	}
+	collect(model["panels"])
+	return panels
+}
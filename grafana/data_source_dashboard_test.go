@@ -0,0 +1,82 @@
+package grafana
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceDashboard_panels(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDashboardConfig_panels,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.grafana_dashboard.test", "title", "Terraform Data Source Test",
+					),
+					resource.TestMatchResourceAttr(
+						"data.grafana_dashboard.test", "config_json", regexp.MustCompile(".*Terraform Data Source Test.*"),
+					),
+					resource.TestCheckResourceAttr(
+						"data.grafana_dashboard.test", "panels.#", "2",
+					),
+					resource.TestCheckResourceAttr(
+						"data.grafana_dashboard.test", "panels.1.title", "Errors",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestDashboardPanels(t *testing.T) {
+	model := configMapOf(`{
+		"rows": [
+			{"panels": [{"id": 1, "title": "Legacy", "type": "graph"}]}
+		],
+		"panels": [
+			{"id": 2, "title": "Requests", "type": "graph"},
+			{"id": 3, "title": "Details", "type": "row", "collapsed": true, "panels": [
+				{"id": 4, "title": "Errors", "type": "singlestat"}
+			]},
+			{"id": 5, "title": "Expanded", "type": "row", "panels": []},
+			{"id": 6, "title": "Logs", "type": "table"}
+		]
+	}`)
+
+	expected := []map[string]interface{}{
+		{"id": 1, "title": "Legacy", "type": "graph"},
+		{"id": 2, "title": "Requests", "type": "graph"},
+		{"id": 4, "title": "Errors", "type": "singlestat"},
+		{"id": 6, "title": "Logs", "type": "table"},
+	}
+	if got := dashboardPanels(model); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+const testAccDataSourceDashboardConfig_panels = `
+resource "grafana_dashboard" "test" {
+    config_json = <<EOT
+{
+    "title": "Terraform Data Source Test",
+    "panels": [
+        {"id": 1, "title": "Requests", "type": "graph"},
+        {"id": 2, "title": "Details", "type": "row", "collapsed": true, "panels": [
+            {"id": 3, "title": "Errors", "type": "singlestat"}
+        ]}
+    ]
+}
+EOT
+}
+
+data "grafana_dashboard" "test" {
+    slug           = "${grafana_dashboard.test.slug}"
+    include_panels = true
+}
+`
@@ -0,0 +1,142 @@
+package grafana
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// dashboardsPageSize is the number of search results requested at a time.
+const dashboardsPageSize = 1000
+
+type dashboardSearchResult struct {
+	Uid         string   `json:"uid"`
+	Title       string   `json:"title"`
+	FolderTitle string   `json:"folderTitle"`
+	Tags        []string `json:"tags"`
+	Uri         string   `json:"uri"`
+	Url         string   `json:"url"`
+}
+
+func DataSourceDashboards() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadDashboards,
+
+		Schema: map[string]*schema.Schema{
+			"query": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"folder_uids": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"dashboards": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"folder_title": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadDashboards(d *schema.ResourceData, meta interface{}) error {
+	query := url.Values{"type": {"dash-db"}}
+	if v, ok := d.GetOk("query"); ok {
+		query.Set("query", v.(string))
+	}
+	for _, tag := range d.Get("tags").([]interface{}) {
+		query.Add("tag", tag.(string))
+	}
+	for _, uid := range d.Get("folder_uids").([]interface{}) {
+		query.Add("folderUIDs", uid.(string))
+	}
+
+	dashboards, err := searchDashboards(meta.(*client), query)
+	if err != nil {
+		return err
+	}
+
+	result := make([]map[string]interface{}, 0, len(dashboards))
+	for _, dashboard := range dashboards {
+		// Dashboards outside of a folder have no folder title.
+		folderTitle := dashboard.FolderTitle
+		if folderTitle == "" {
+			folderTitle = "General"
+		}
+		result = append(result, map[string]interface{}{
+			"uid":          dashboard.Uid,
+			"title":        dashboard.Title,
+			"folder_title": folderTitle,
+			"tags":         dashboard.Tags,
+		})
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(query.Encode())))
+	d.Set("dashboards", result)
+	return nil
+}
+
+// searchDashboards returns every search result matching query, requesting
+// them a page at a time. Grafana before 7.0 ignores the page parameter and
+// returns the first page again, so a page without new results ends the
+// search as well.
+func searchDashboards(c *client, query url.Values) ([]dashboardSearchResult, error) {
+	query.Set("limit", strconv.Itoa(dashboardsPageSize))
+
+	var dashboards []dashboardSearchResult
+	seen := make(map[string]bool)
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var results []dashboardSearchResult
+		if err := c.request("GET", "/api/search", query, nil, &results); err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, r := range results {
+			if seen[r.Uid] {
+				continue
+			}
+			seen[r.Uid] = true
+			dashboards = append(dashboards, r)
+			added++
+		}
+		if len(results) < dashboardsPageSize || added == 0 {
+			return dashboards, nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestSearchDashboards_paginates(t *testing.T) {
+	all := make([]dashboardSearchResult, 2500)
+	for i := range all {
+		all[i] = dashboardSearchResult{Uid: fmt.Sprintf("dash-%d", i), Title: "PII", Tags: []string{"pii"}}
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if got := r.URL.Query()["tag"]; len(got) != 1 || got[0] != "pii" {
+			t.Errorf("expected tag pii, got %v", got)
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start, end := (page-1)*limit, page*limit
+		if start > len(all) {
+			start = len(all)
+		}
+		if end > len(all) {
+			end = len(all)
+		}
+		json.NewEncoder(w).Encode(all[start:end])
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboards, err := searchDashboards(c, url.Values{"tag": {"pii"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dashboards) != len(all) {
+		t.Fatalf("expected %d dashboards, got %d", len(all), len(dashboards))
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestSearchDashboards_withoutPages(t *testing.T) {
+	// Grafana before 7.0 returns the same results whatever the page.
+	all := make([]dashboardSearchResult, dashboardsPageSize)
+	for i := range all {
+		all[i] = dashboardSearchResult{Uid: fmt.Sprintf("dash-%d", i)}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(all)
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboards, err := searchDashboards(c, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dashboards) != len(all) {
+		t.Fatalf("expected %d dashboards, got %d", len(all), len(dashboards))
+	}
+}
@@ -0,0 +1,78 @@
+package grafana
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceDataSource() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadDataSourceData,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"uid"},
+			},
+			"uid": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name"},
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"is_default": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func ReadDataSourceData(d *schema.ResourceData, meta interface{}) error {
+	var path, ref string
+	if name := d.Get("name").(string); name != "" {
+		path, ref = "/api/datasources/name/"+url.PathEscape(name), fmt.Sprintf("name '%s'", name)
+	} else if uid := d.Get("uid").(string); uid != "" {
+		path, ref = "/api/datasources/uid/"+url.PathEscape(uid), fmt.Sprintf("uid '%s'", uid)
+	} else {
+		return errors.New("Error: One of name or uid must be set to look up a Grafana datasource.")
+	}
+
+	dataSource := struct {
+		Id        int64  `json:"id"`
+		Uid       string `json:"uid"`
+		Name      string `json:"name"`
+		Type      string `json:"type"`
+		URL       string `json:"url"`
+		IsDefault bool   `json:"isDefault"`
+	}{}
+	err := meta.(*client).request("GET", path, nil, nil, &dataSource)
+	if err != nil {
+		if err.Error() == "404 Not Found" {
+			return fmt.Errorf("Error: A Grafana datasource with the %s does not exist.", ref)
+		}
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(dataSource.Id, 10))
+	d.Set("name", dataSource.Name)
+	d.Set("uid", dataSource.Uid)
+	d.Set("type", dataSource.Type)
+	d.Set("url", dataSource.URL)
+	d.Set("is_default", dataSource.IsDefault)
+	return nil
+}
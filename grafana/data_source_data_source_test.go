@@ -0,0 +1,67 @@
+package grafana
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceDataSource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDataSourceConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"data.grafana_data_source.by_name", "id", "grafana_data_source.test", "id",
+					),
+					resource.TestMatchResourceAttr(
+						"data.grafana_data_source.by_name", "uid", regexp.MustCompile(`\w+`),
+					),
+					resource.TestCheckResourceAttr(
+						"data.grafana_data_source.by_name", "type", "influxdb",
+					),
+					resource.TestCheckResourceAttr(
+						"data.grafana_data_source.by_name", "url", "http://terraform-acc-test.invalid/",
+					),
+					resource.TestCheckResourceAttr(
+						"data.grafana_data_source.by_name", "is_default", "false",
+					),
+					resource.TestCheckResourceAttrPair(
+						"data.grafana_data_source.by_uid", "name", "grafana_data_source.test", "name",
+					),
+				),
+			},
+			{
+				Config:      testAccDataSourceDataSourceConfig_missing,
+				ExpectError: regexp.MustCompile("datasource with the name 'terraform-acc-missing' does not exist"),
+			},
+		},
+	})
+}
+
+const testAccDataSourceDataSourceConfig_basic = `
+resource "grafana_data_source" "test" {
+    type = "influxdb"
+    name = "terraform-acc-data-source-lookup"
+    url = "http://terraform-acc-test.invalid/"
+    database_name = "terraform-acc-test"
+}
+
+data "grafana_data_source" "by_name" {
+    name = "${grafana_data_source.test.name}"
+}
+
+data "grafana_data_source" "by_uid" {
+    uid = "${data.grafana_data_source.by_name.uid}"
+}
+`
+
+const testAccDataSourceDataSourceConfig_missing = `
+data "grafana_data_source" "missing" {
+    name = "terraform-acc-missing"
+}
+`
@@ -0,0 +1,45 @@
+package grafana
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceInstance() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadInstance,
+
+		Schema: map[string]*schema.Schema{
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"edition": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"enterprise": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"commit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func ReadInstance(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	instance, err := c.instance()
+	if err != nil {
+		return err
+	}
+
+	d.SetId(c.baseURL.String())
+	d.Set("version", instance.Version)
+	d.Set("edition", instance.Edition)
+	d.Set("enterprise", instance.enterprise())
+	d.Set("commit", instance.Commit)
+	return nil
+}
@@ -0,0 +1,81 @@
+package grafana
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestReadInstance(t *testing.T) {
+	cases := []struct {
+		health, settings    string
+		version, edition    string
+		enterprise, atLeast bool
+	}{
+		{
+			`{"commit": "abc", "database": "ok", "version": "8.3.4"}`,
+			`{"buildInfo": {"version": "8.3.4", "edition": "Enterprise"}}`,
+			"8.3.4", "Enterprise", true, true,
+		},
+		{
+			`{"commit": "abc", "database": "ok", "version": "7.5.0"}`,
+			`{"buildInfo": {"version": "7.5.0", "edition": "Open Source"}}`,
+			"7.5.0", "Open Source", false, false,
+		},
+		// Grafana 5.x has neither a version in its health check nor an
+		// edition.
+		{
+			`{"commit": "abc", "database": "ok"}`,
+			`{"buildInfo": {"version": "5.2.0"}}`,
+			"5.2.0", "Open Source", false, false,
+		},
+	}
+	for _, tc := range cases {
+		requests := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			switch r.URL.Path {
+			case "/api/health":
+				fmt.Fprint(w, tc.health)
+			case "/api/frontend/settings":
+				fmt.Fprint(w, tc.settings)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+
+		c, err := newClient("token", server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := schema.TestResourceDataRaw(t, DataSourceInstance().Schema, map[string]interface{}{})
+		if err := ReadInstance(d, c); err != nil {
+			t.Fatal(err)
+		}
+		if got := d.Get("version").(string); got != tc.version {
+			t.Errorf("expected version %s, got %s", tc.version, got)
+		}
+		if got := d.Get("edition").(string); got != tc.edition {
+			t.Errorf("expected edition %s, got %s", tc.edition, got)
+		}
+		if got := d.Get("enterprise").(bool); got != tc.enterprise {
+			t.Errorf("%s: expected enterprise to be %t", tc.version, tc.enterprise)
+		}
+
+		// The server is only asked once.
+		instance, err := c.instance()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := instance.atLeast("8.0.0"); got != tc.atLeast {
+			t.Errorf("%s: expected atLeast(8.0.0) to be %t", tc.version, tc.atLeast)
+		}
+		if requests != 2 {
+			t.Errorf("expected 2 requests, got %d", requests)
+		}
+		server.Close()
+	}
+}
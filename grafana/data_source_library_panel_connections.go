@@ -0,0 +1,54 @@
+package grafana
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceLibraryPanelConnections() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadLibraryPanelConnections,
+
+		Schema: map[string]*schema.Schema{
+			"uid": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"dashboard_uids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func ReadLibraryPanelConnections(d *schema.ResourceData, meta interface{}) error {
+	uid := d.Get("uid").(string)
+	result := struct {
+		Result []struct {
+			ConnectionUid string `json:"connectionUid"`
+		} `json:"result"`
+	}{}
+	err := meta.(*client).request("GET", fmt.Sprintf("/api/library-elements/%s/connections", url.PathEscape(uid)), nil, nil, &result)
+	if err != nil {
+		if err.Error() == "404 Not Found" {
+			return fmt.Errorf("Error: A Grafana library panel with the uid '%s' does not exist.", uid)
+		}
+		return err
+	}
+
+	// A panel that isn't used anywhere has an empty list of connections.
+	dashboardUids := make([]string, 0, len(result.Result))
+	for _, c := range result.Result {
+		dashboardUids = append(dashboardUids, c.ConnectionUid)
+	}
+
+	d.SetId(uid)
+	d.Set("dashboard_uids", dashboardUids)
+	return nil
+}
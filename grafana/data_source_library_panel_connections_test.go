@@ -0,0 +1,54 @@
+package grafana
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestReadLibraryPanelConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/library-elements/used/connections":
+			fmt.Fprint(w, `{"result": [{"id": 1, "connectionUid": "dash-a"}, {"id": 2, "connectionUid": "dash-b"}]}`)
+		case "/api/library-elements/unused/connections":
+			fmt.Fprint(w, `{"result": []}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string][]string{
+		"used":   {"dash-a", "dash-b"},
+		"unused": {},
+	}
+	for uid, expected := range cases {
+		d := schema.TestResourceDataRaw(t, DataSourceLibraryPanelConnections().Schema, map[string]interface{}{"uid": uid})
+		if err := ReadLibraryPanelConnections(d, c); err != nil {
+			t.Fatalf("%s: %s", uid, err)
+		}
+		got := d.Get("dashboard_uids").([]interface{})
+		if len(got) != len(expected) {
+			t.Fatalf("%s: expected %v, got %v", uid, expected, got)
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Fatalf("%s: expected %v, got %v", uid, expected, got)
+			}
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, DataSourceLibraryPanelConnections().Schema, map[string]interface{}{"uid": "missing"})
+	if err := ReadLibraryPanelConnections(d, c); err == nil {
+		t.Fatal("expected an error for a missing library panel")
+	}
+}
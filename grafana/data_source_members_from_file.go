@@ -0,0 +1,124 @@
+package grafana
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceMembersFromFile() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadMembersFromFile,
+
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: ValidateMembersFileFormat,
+			},
+			"emails": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func ValidateMembersFileFormat(formatI interface{}, k string) ([]string, []error) {
+	format := formatI.(string)
+	if format != "json" && format != "csv" {
+		return nil, []error{fmt.Errorf("%s must be either \"json\" or \"csv\", got %q", k, format)}
+	}
+	return nil, nil
+}
+
+func ReadMembersFromFile(d *schema.ResourceData, meta interface{}) error {
+	path := d.Get("path").(string)
+	format := d.Get("format").(string)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	emails, err := readMemberEmails(path, format)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(path)
+	d.Set("emails", emails)
+	return nil
+}
+
+// readMemberEmails reads a list of emails from either a JSON array of strings
+// or a CSV file. CSV files with a header row containing an "email" column use
+// that column, otherwise the first column of every row is used. Emails are
+// trimmed and lowercased, and like the organization role lists, a user may
+// only be listed once.
+func readMemberEmails(path, format string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var raw []string
+	switch format {
+	case "json":
+		if err := json.NewDecoder(f).Decode(&raw); err != nil {
+			return nil, fmt.Errorf("Error parsing %s: %s", path, err)
+		}
+	case "csv":
+		r := csv.NewReader(f)
+		r.FieldsPerRecord = -1
+		records, err := r.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing %s: %s", path, err)
+		}
+		column := 0
+		if len(records) > 0 {
+			for i, field := range records[0] {
+				if strings.EqualFold(strings.TrimSpace(field), "email") {
+					column = i
+					records = records[1:]
+					break
+				}
+			}
+		}
+		for _, record := range records {
+			if column < len(record) {
+				raw = append(raw, record[column])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("Error: Unable to determine the format of %s, set format to \"json\" or \"csv\".", path)
+	}
+
+	emails := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, email := range raw {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" {
+			continue
+		}
+		// Sanity check that a user isn't specified twice
+		if seen[email] {
+			return nil, errors.New(fmt.Sprintf("Error: User '%s' cannot be specified multiple times.", email))
+		}
+		seen[email] = true
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
@@ -0,0 +1,67 @@
+package grafana
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadMemberEmails(t *testing.T) {
+	cases := []struct {
+		name, format, content string
+		expected              []string
+	}{
+		{
+			"members.json", "json",
+			`["john.doe@example.com", " Jane.Doe@example.com "]`,
+			[]string{"john.doe@example.com", "jane.doe@example.com"},
+		},
+		{
+			"members.csv", "csv",
+			"john.doe@example.com\n\njane.doe@example.com\n",
+			[]string{"john.doe@example.com", "jane.doe@example.com"},
+		},
+		{
+			"members.csv", "csv",
+			"name,Email\nJohn,john.doe@example.com\nJane,jane.doe@example.com\n",
+			[]string{"john.doe@example.com", "jane.doe@example.com"},
+		},
+	}
+	for _, c := range cases {
+		path := testMembersFile(t, c.name, c.content)
+		defer os.RemoveAll(filepath.Dir(path))
+
+		emails, err := readMemberEmails(path, c.format)
+		if err != nil {
+			t.Fatalf("%s: %s", c.content, err)
+		}
+		if !reflect.DeepEqual(emails, c.expected) {
+			t.Errorf("%s: expected %v, got %v", c.content, c.expected, emails)
+		}
+	}
+}
+
+func TestReadMemberEmails_duplicate(t *testing.T) {
+	path := testMembersFile(t, "members.json", `["john.doe@example.com", "John.Doe@example.com "]`)
+	defer os.RemoveAll(filepath.Dir(path))
+
+	_, err := readMemberEmails(path, "json")
+	if err == nil || !strings.Contains(err.Error(), "'john.doe@example.com' cannot be specified multiple times") {
+		t.Fatalf("expected a duplicate user error, got %v", err)
+	}
+}
+
+func testMembersFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "terraform-provider-grafana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,78 @@
+package grafana
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceOrganization() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadOrganizationData,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"include_users": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"users": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"login": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"email": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadOrganizationData(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	name := d.Get("name").(string)
+	org, err := c.gapi.OrgByName(name)
+	if err != nil {
+		if err.Error() == "404 Not Found" {
+			return fmt.Errorf("Error: A Grafana Organization with the name '%s' does not exist.", name)
+		}
+		return err
+	}
+
+	users := []map[string]interface{}{}
+	if d.Get("include_users").(bool) {
+		orgUsers, err := c.orgUsers.get(org.Id, c.listOrgUsers)
+		if err != nil {
+			return err
+		}
+		for _, u := range orgUsers {
+			users = append(users, map[string]interface{}{
+				"login": u.Login,
+				"email": u.Email,
+				"role":  u.Role,
+			})
+		}
+	}
+
+	d.SetId(strconv.FormatInt(org.Id, 10))
+	d.Set("name", org.Name)
+	d.Set("users", users)
+	return nil
+}
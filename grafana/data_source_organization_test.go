@@ -0,0 +1,78 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	gapi "github.com/nytm/go-grafana-api"
+)
+
+func TestListOrgUsers_paginates(t *testing.T) {
+	all := make([]gapi.OrgUser, 2500)
+	for i := range all {
+		all[i] = gapi.OrgUser{OrgId: 2, UserId: int64(i + 1), Email: fmt.Sprintf("user%d@example.com", i+1), Role: "Viewer"}
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/orgs/2/users/search" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("perpage"))
+		start, end := (page-1)*perPage, page*perPage
+		if end > len(all) {
+			end = len(all)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"totalCount": len(all),
+			"orgUsers":   all[start:end],
+		})
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := c.listOrgUsers(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != len(all) {
+		t.Fatalf("expected %d users, got %d", len(all), len(users))
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestListOrgUsers_unpaginated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/orgs/2/users" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode([]gapi.OrgUser{
+			{OrgId: 2, UserId: 1, Email: "admin@localhost", Login: "admin", Role: "Admin"},
+		})
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := c.listOrgUsers(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Login != "admin" {
+		t.Fatalf("expected the admin user, got %v", users)
+	}
+}
@@ -0,0 +1,77 @@
+package grafana
+
+import (
+	"encoding/json"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceReports() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadReports,
+
+		Schema: map[string]*schema.Schema{
+			"reports": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dashboard_uid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"schedule": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadReports(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	var reports []struct {
+		Id           int64           `json:"id"`
+		Name         string          `json:"name"`
+		DashboardUid string          `json:"dashboardUid"`
+		Schedule     json.RawMessage `json:"schedule"`
+	}
+	err := c.request("GET", "/api/reports", nil, nil, &reports)
+	// Reporting is a Grafana Enterprise feature, other editions don't serve
+	// the endpoint at all.
+	if err != nil && err.Error() == "404 Not Found" {
+		log.Printf("[WARN] listing reports requires Grafana Enterprise, %s doesn't support reporting", c.baseURL.String())
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+
+	result := []map[string]interface{}{}
+	for _, r := range reports {
+		result = append(result, map[string]interface{}{
+			"id":            r.Id,
+			"name":          r.Name,
+			"dashboard_uid": r.DashboardUid,
+			"schedule":      string(r.Schedule),
+		})
+	}
+
+	// The API only lists the reports of the current organization, there is
+	// nothing else to identify the result by.
+	d.SetId("reports")
+	d.Set("reports", result)
+	return nil
+}
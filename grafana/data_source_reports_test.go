@@ -0,0 +1,61 @@
+package grafana
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestReadReports(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/reports" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[{"id": 4, "name": "Weekly", "dashboardUid": "dash-a", "schedule": {"frequency": "weekly", "hour": 8}}]`)
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, DataSourceReports().Schema, map[string]interface{}{})
+	if err := ReadReports(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := d.Get("reports.#").(int); n != 1 {
+		t.Fatalf("expected 1 report, got %d", n)
+	}
+	expected := map[string]interface{}{
+		"reports.0.id":            4,
+		"reports.0.name":          "Weekly",
+		"reports.0.dashboard_uid": "dash-a",
+		"reports.0.schedule":      `{"frequency": "weekly", "hour": 8}`,
+	}
+	for key, value := range expected {
+		if got := d.Get(key); got != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, got)
+		}
+	}
+}
+
+func TestReadReports_notEnterprise(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, DataSourceReports().Schema, map[string]interface{}{})
+	if err := ReadReports(d, c); err != nil {
+		t.Fatalf("expected no error without reporting, got %s", err)
+	}
+	if n := d.Get("reports.#").(int); n != 0 {
+		t.Fatalf("expected no reports, got %d", n)
+	}
+}
@@ -0,0 +1,63 @@
+package grafana
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func DataSourceUserOrgs() *schema.Resource {
+	return &schema.Resource{
+		Read: ReadUserOrgs,
+
+		Schema: map[string]*schema.Schema{
+			"orgs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"org_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ReadUserOrgs(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	// The organizations of the signed in user aren't paginated, a user only
+	// belongs to a handful of them.
+	var orgs []struct {
+		OrgId int64  `json:"orgId"`
+		Name  string `json:"name"`
+		Role  string `json:"role"`
+	}
+	if err := c.request("GET", "/api/user/orgs", nil, nil, &orgs); err != nil {
+		return err
+	}
+
+	result := []map[string]interface{}{}
+	for _, o := range orgs {
+		result = append(result, map[string]interface{}{
+			"org_id": o.OrgId,
+			"name":   o.Name,
+			"role":   o.Role,
+		})
+	}
+
+	// The organizations are always those of the user the provider
+	// authenticates as.
+	d.SetId("user_orgs")
+	d.Set("orgs", result)
+	return nil
+}
@@ -0,0 +1,46 @@
+package grafana
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestReadUserOrgs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/user/orgs" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		fmt.Fprint(w, `[{"orgId": 1, "name": "Main Org.", "role": "Admin"}, {"orgId": 3, "name": "Ops", "role": "Viewer"}]`)
+	}))
+	defer server.Close()
+
+	c, err := newClient("admin:admin", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, DataSourceUserOrgs().Schema, map[string]interface{}{})
+	if err := ReadUserOrgs(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := d.Get("orgs.#").(int); n != 2 {
+		t.Fatalf("expected 2 organizations, got %d", n)
+	}
+	expected := map[string]interface{}{
+		"orgs.0.org_id": 1,
+		"orgs.0.name":   "Main Org.",
+		"orgs.0.role":   "Admin",
+		"orgs.1.org_id": 3,
+		"orgs.1.name":   "Ops",
+		"orgs.1.role":   "Viewer",
+	}
+	for key, value := range expected {
+		if got := d.Get(key); got != value {
+			t.Errorf("expected %s to be %v, got %v", key, value, got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package grafana
+
+import (
+	"sync"
+
+	version "github.com/hashicorp/go-version"
+)
+
+// instance describes the Grafana server the provider talks to, so that
+// resources can pick API code paths and gate Enterprise features without
+// detecting the server themselves.
+type instance struct {
+	Version string
+	Edition string
+	Commit  string
+}
+
+// instanceCache detects the Grafana server once per provider instance, on
+// first use rather than when the provider is configured, so that offline
+// operations such as validate don't need the server.
+type instanceCache struct {
+	mu       sync.Mutex
+	instance *instance
+}
+
+// instance returns the version and edition of the Grafana server, detecting
+// them on the first call.
+func (c *client) instance() (*instance, error) {
+	c.instanceCache.mu.Lock()
+	defer c.instanceCache.mu.Unlock()
+	if c.instanceCache.instance != nil {
+		return c.instanceCache.instance, nil
+	}
+
+	health := struct {
+		Commit  string `json:"commit"`
+		Version string `json:"version"`
+	}{}
+	if err := c.request("GET", "/api/health", nil, nil, &health); err != nil {
+		return nil, err
+	}
+	settings := struct {
+		BuildInfo struct {
+			Version string `json:"version"`
+			Edition string `json:"edition"`
+		} `json:"buildInfo"`
+	}{}
+	if err := c.request("GET", "/api/frontend/settings", nil, nil, &settings); err != nil {
+		return nil, err
+	}
+
+	i := &instance{
+		Version: health.Version,
+		Edition: settings.BuildInfo.Edition,
+		Commit:  health.Commit,
+	}
+	// Grafana before 5.4 has no version in its health check and before 6.0
+	// no edition, which means it's the open source one.
+	if i.Version == "" {
+		i.Version = settings.BuildInfo.Version
+	}
+	if i.Edition == "" {
+		i.Edition = "Open Source"
+	}
+	c.instanceCache.instance = i
+	return i, nil
+}
+
+// enterprise tells whether the server is Grafana Enterprise.
+func (i *instance) enterprise() bool {
+	return i.Edition == "Enterprise"
+}
+
+// atLeast tells whether the server's version is at least v. Versions that
+// can't be parsed, such as those of development builds, are assumed recent.
+func (i *instance) atLeast(v string) bool {
+	current, err := version.NewVersion(i.Version)
+	if err != nil {
+		return true
+	}
+	return current.Compare(version.Must(version.NewVersion(v))) >= 0
+}
@@ -1,10 +1,11 @@
 package grafana
 
 import (
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
-
-	gapi "github.com/nytm/go-grafana-api"
 )
 
 func Provider() terraform.ResourceProvider {
@@ -23,14 +24,60 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("GRAFANA_AUTH", nil),
 				Description: "Credentials for accessing the Grafana API.",
 			},
+			"org_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRAFANA_ORG_ID", 0),
+				Description: "Organization to manage resources in. 0 means the current organization of the authenticated user.",
+			},
+			"http_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRAFANA_HTTP_TIMEOUT", 60),
+				Description: "Timeout in seconds for requests to the Grafana API. 0 means no timeout.",
+			},
+			"max_idle_conns": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRAFANA_MAX_IDLE_CONNS", 100),
+				Description: "Maximum number of idle connections kept open to the Grafana server.",
+			},
+			"max_conns_per_host": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRAFANA_MAX_CONNS_PER_HOST", 0),
+				Description: "Maximum number of connections to the Grafana server. 0 means no limit.",
+			},
+			"min_refresh_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("GRAFANA_MIN_REFRESH_INTERVAL", ""),
+				ValidateFunc: ValidateInterval,
+				Description:  "Shortest refresh interval dashboards may have, e.g. 30s. Unset means any.",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"grafana_alert_notification": ResourceAlertNotification(),
-			"grafana_dashboard":          ResourceDashboard(),
-			"grafana_data_source":        ResourceDataSource(),
-			"grafana_folder":             ResourceFolder(),
-			"grafana_organization":       ResourceOrganization(),
+			"grafana_alert_notification":      ResourceAlertNotification(),
+			"grafana_dashboard":               ResourceDashboard(),
+			"grafana_data_source":             ResourceDataSource(),
+			"grafana_folder":                  ResourceFolder(),
+			"grafana_organization":            ResourceOrganization(),
+			"grafana_organization_membership": ResourceOrganizationMembership(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"grafana_alert_notification":        DataSourceAlertNotification(),
+			"grafana_annotations":               DataSourceAnnotations(),
+			"grafana_dashboard":                 DataSourceDashboard(),
+			"grafana_dashboards":                DataSourceDashboards(),
+			"grafana_data_source":               DataSourceDataSource(),
+			"grafana_instance":                  DataSourceInstance(),
+			"grafana_library_panel_connections": DataSourceLibraryPanelConnections(),
+			"grafana_members_from_file":         DataSourceMembersFromFile(),
+			"grafana_organization":              DataSourceOrganization(),
+			"grafana_reports":                   DataSourceReports(),
+			"grafana_user_orgs":                 DataSourceUserOrgs(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -38,8 +85,31 @@ func Provider() terraform.ResourceProvider {
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	return gapi.New(
+	c, err := newClient(
 		d.Get("auth").(string),
 		d.Get("url").(string),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	// All requests go to a single host, so every idle connection may be kept
+	// for it rather than the handful allowed per host by default.
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.MaxIdleConns = d.Get("max_idle_conns").(int)
+	transport.MaxIdleConnsPerHost = transport.MaxIdleConns
+	transport.MaxConnsPerHost = d.Get("max_conns_per_host").(int)
+	c.gapi.Transport = transport
+	if orgId := d.Get("org_id").(int); orgId > 0 {
+		c.gapi.Transport = &orgTransport{base: transport, orgId: int64(orgId)}
+	}
+	c.gapi.Timeout = time.Duration(d.Get("http_timeout").(int)) * time.Second
+	if interval := d.Get("min_refresh_interval").(string); interval != "" {
+		c.minRefreshInterval, err = parseInterval(interval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
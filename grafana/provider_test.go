@@ -1,8 +1,13 @@
 package grafana
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
@@ -44,6 +49,111 @@ func TestProvider_impl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
 
+func TestProviderConfigure_transport(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"url":                "http://localhost:3000",
+		"auth":               "admin:admin",
+		"http_timeout":       5,
+		"max_idle_conns":     20,
+		"max_conns_per_host": 10,
+	})
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := meta.(*client).gapi
+	if c.Timeout != 5*time.Second {
+		t.Errorf("expected a 5s timeout, got %s", c.Timeout)
+	}
+	transport, ok := c.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected an *http.Transport, got %T", c.Transport)
+	}
+	if transport.MaxIdleConns != 20 || transport.MaxIdleConnsPerHost != 20 {
+		t.Errorf("expected 20 idle connections, got %d (%d per host)", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	}
+	if transport.MaxConnsPerHost != 10 {
+		t.Errorf("expected 10 connections per host, got %d", transport.MaxConnsPerHost)
+	}
+}
+
+func TestProviderConfigure_transportDefaults(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"url":  "http://localhost:3000",
+		"auth": "admin:admin",
+	})
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := meta.(*client).gapi
+	if c.Timeout != 60*time.Second {
+		t.Errorf("expected a 60s timeout, got %s", c.Timeout)
+	}
+	transport := c.Transport.(*http.Transport)
+	if transport.MaxIdleConns != 100 || transport.MaxConnsPerHost != 0 {
+		t.Errorf("expected 100 idle connections and no connection limit, got %d and %d", transport.MaxIdleConns, transport.MaxConnsPerHost)
+	}
+}
+
+func TestProviderConfigure_minRefreshInterval(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"url":                  "http://localhost:3000",
+		"auth":                 "admin:admin",
+		"min_refresh_interval": "30s",
+	})
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := meta.(*client).minRefreshInterval; got != 30*time.Second {
+		t.Errorf("expected a minimum refresh interval of 30s, got %s", got)
+	}
+}
+
+func TestProviderConfigure_orgId(t *testing.T) {
+	var orgIds []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgIds = append(orgIds, r.Header.Get("X-Grafana-Org-Id"))
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	// Each aliased provider makes its requests in its own organization.
+	for _, orgId := range []int{0, 2, 3} {
+		d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+			"url":    server.URL,
+			"auth":   "admin:admin",
+			"org_id": orgId,
+		})
+		meta, err := providerConfigure(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := meta.(*client).request("GET", "/api/org", nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Requests choosing an organization themselves keep it.
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"url":    server.URL,
+		"auth":   "admin:admin",
+		"org_id": 2,
+	})
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := meta.(*client).orgRequest(4, "GET", "/api/org/preferences", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"", "2", "3", "4"}
+	if !reflect.DeepEqual(orgIds, expected) {
+		t.Fatalf("expected requests in organizations %q, got %q", expected, orgIds)
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("GRAFANA_URL"); v == "" {
 		t.Fatal("GRAFANA_URL must be set for acceptance tests")
@@ -34,16 +34,17 @@ func ResourceAlertNotification() *schema.Resource {
 			},
 
 			"settings": {
-				Type:      schema.TypeMap,
-				Optional:  true,
-				Sensitive: true,
+				Type:         schema.TypeMap,
+				Optional:     true,
+				Sensitive:    true,
+				ValidateFunc: ValidateAlertNotificationSettings,
 			},
 		},
 	}
 }
 
 func CreateAlertNotification(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 
 	alertNotification, err := makeAlertNotification(d)
 	if err != nil {
@@ -61,7 +62,7 @@ func CreateAlertNotification(d *schema.ResourceData, meta interface{}) error {
 }
 
 func UpdateAlertNotification(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 
 	alertNotification, err := makeAlertNotification(d)
 	if err != nil {
@@ -72,7 +73,7 @@ func UpdateAlertNotification(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadAlertNotification(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 
 	idStr := d.Id()
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -112,7 +113,7 @@ func ReadAlertNotification(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteAlertNotification(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 
 	idStr := d.Id()
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -123,6 +124,16 @@ func DeleteAlertNotification(d *schema.ResourceData, meta interface{}) error {
 	return client.DeleteAlertNotification(id)
 }
 
+// ValidateAlertNotificationSettings checks the recipients of email
+// notification channels, which are the only settings holding addresses.
+func ValidateAlertNotificationSettings(settingsI interface{}, k string) ([]string, []error) {
+	addresses, ok := settingsI.(map[string]interface{})["addresses"].(string)
+	if !ok {
+		return nil, nil
+	}
+	return ValidateEmailList(addresses, k+".addresses")
+}
+
 func makeAlertNotification(d *schema.ResourceData) (*gapi.AlertNotification, error) {
 	idStr := d.Id()
 	var id int64
@@ -55,7 +55,7 @@ func testAccAlertNotificationCheckExists(rn string, a *gapi.AlertNotification) r
 			return fmt.Errorf("resource id is malformed")
 		}
 
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		gotAlertNotification, err := client.AlertNotification(id)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
@@ -69,7 +69,7 @@ func testAccAlertNotificationCheckExists(rn string, a *gapi.AlertNotification) r
 
 func testAccAlertNotificationCheckDestroy(a *gapi.AlertNotification) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		alert, err := client.AlertNotification(a.Id)
 		if err == nil && alert != nil {
 			return fmt.Errorf("alert-notification still exists")
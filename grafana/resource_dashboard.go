@@ -1,15 +1,34 @@
 package grafana
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	ctyyaml "github.com/zclconf/go-cty-yaml"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	gapi "github.com/nytm/go-grafana-api"
 )
 
+// dashboardSaveRequest is gapi.Dashboard along with the save message, which
+// go-grafana-api doesn't send.
+type dashboardSaveRequest struct {
+	Model     map[string]interface{} `json:"dashboard"`
+	Folder    int64                  `json:"folderId"`
+	Overwrite bool                   `json:"overwrite"`
+	Message   string                 `json:"message,omitempty"`
+}
+
 func ResourceDashboard() *schema.Resource {
 	return &schema.Resource{
 		Create: CreateDashboard,
@@ -19,6 +38,7 @@ func ResourceDashboard() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: CustomizeDashboardDiff,
 
 		Schema: map[string]*schema.Schema{
 			"slug": {
@@ -27,31 +47,133 @@ func ResourceDashboard() *schema.Resource {
 			},
 
 			"folder": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				ForceNew: true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"folder_uid"},
 			},
 
+			"folder_uid": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"folder"},
+			},
+
+			// Holds the JSON of the dashboard saved from config_yaml when
+			// that's used instead.
 			"config_json": {
-				Type:         schema.TypeString,
-				Required:     true,
-				StateFunc:    NormalizeDashboardConfigJSON,
-				ValidateFunc: ValidateDashboardConfigJSON,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"config_yaml"},
+				StateFunc:     NormalizeDashboardConfigJSON,
+				ValidateFunc:  ValidateDashboardConfigJSON,
+			},
+
+			"config_yaml": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"config_json"},
+				StateFunc:     NormalizeDashboardConfigYAML,
+				ValidateFunc:  ValidateDashboardConfigYAML,
+			},
+
+			"inputs": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"strip_dashboard_alerts": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"datasource_uid_map": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"tags": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			// Only used when creating the dashboard, see CreateDashboard.
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			// The message is only recorded in the version history when the
+			// dashboard is saved, see CustomizeDashboardDiff.
+			"message": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"version": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"config_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"folder_title": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 		},
 	}
 }
 
 func CreateDashboard(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client)
 
-	dashboard := gapi.Dashboard{}
+	dashboard := dashboardSaveRequest{}
 
-	dashboard.Model = prepareDashboardModel(d.Get("config_json").(string))
-
-	dashboard.Folder = int64(d.Get("folder").(int))
+	model, err := prepareDashboardModel(dashboardConfigJSON(d), d)
+	if err != nil {
+		return err
+	}
+	dashboard.Model = model
 
-	resp, err := client.NewDashboard(dashboard)
+	dashboard.Folder, err = client.dashboardFolderId(d)
+	if err != nil {
+		return err
+	}
+	dashboard.Message = d.Get("message").(string)
+
+	// Grafana refuses to save a new dashboard over one with the same title in
+	// the same folder unless asked to overwrite it.
+	resp := gapi.DashboardSaveResponse{}
+	err = client.request("POST", "/api/dashboards/db", nil, dashboard, &resp)
+	if isDashboardNameExists(err) {
+		if !d.Get("adopt_existing").(bool) {
+			return fmt.Errorf("Error: A Grafana dashboard with the title '%s' already exists in the folder. Set adopt_existing to take it over.", model["title"])
+		}
+		log.Printf("[WARN] adopting existing dashboard with the title '%s'", model["title"])
+		dashboard.Overwrite = true
+		err = client.request("POST", "/api/dashboards/db", nil, dashboard, &resp)
+	}
 	if err != nil {
 		return err
 	}
@@ -61,8 +183,23 @@ func CreateDashboard(d *schema.ResourceData, meta interface{}) error {
 	return ReadDashboard(d, meta)
 }
 
+// isDashboardNameExists tells whether saving a dashboard failed because
+// another dashboard has the same title in the same folder.
+func isDashboardNameExists(err error) bool {
+	apiErr, ok := err.(*apiError)
+	if !ok || !strings.HasPrefix(apiErr.status, "412") {
+		return false
+	}
+	result := struct {
+		Status string `json:"status"`
+	}{}
+	json.Unmarshal(apiErr.body, &result)
+	return result.Status == "name-exists"
+}
+
 func ReadDashboard(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
+	client := c.gapi
 
 	slug := d.Id()
 
@@ -84,25 +221,96 @@ func ReadDashboard(d *schema.ResourceData, meta interface{}) error {
 
 	configJSON := NormalizeDashboardConfigJSON(string(configJSONBytes))
 
+	// Grafana stores dashboards declaring inputs with their placeholders
+	// substituted, without the alerts that were stripped, with the datasource
+	// uids mapped and with the tags of the tags attribute. Keep the configured
+	// JSON as long as it matches.
+	configured := dashboardConfigJSON(d)
+	_, hasInputs := configMapOf(configured)["__inputs"]
+	tags := d.Get("tags").([]interface{})
+	if hasInputs || d.Get("strip_dashboard_alerts").(bool) || len(d.Get("datasource_uid_map").(map[string]interface{})) > 0 || len(tags) > 0 {
+		model, err := prepareDashboardModel(configured, d)
+		if err == nil {
+			modelJSON, _ := json.Marshal(model)
+			if NormalizeDashboardConfigJSON(string(modelJSON)) == configJSON {
+				configJSON = configured
+			}
+		}
+	}
+
+	// Dashboards outside of a folder live in the General folder, which
+	// can't be looked up through the folder API.
+	folderTitle, folderUid := "General", ""
+	if dashboard.Folder != 0 {
+		folder, err := client.Folder(dashboard.Folder)
+		if err != nil {
+			return err
+		}
+		folderTitle, folderUid = folder.Title, folder.Uid
+	}
+
+	uid, _ := dashboard.Model["uid"].(string)
+	version, _ := dashboard.Model["version"].(float64)
+
 	d.SetId(dashboard.Meta.Slug)
 	d.Set("slug", dashboard.Meta.Slug)
+	if d.Get("config_yaml").(string) != "" {
+		configJSON = NormalizeDashboardConfigJSON(configJSON)
+		d.Set("config_yaml", configJSON)
+	}
 	d.Set("config_json", configJSON)
-	d.Set("folder", dashboard.Folder)
+	// Only the way the folder is configured is kept up to date, the other
+	// would show up as a conflicting change.
+	if d.Get("folder_uid").(string) != "" {
+		d.Set("folder_uid", folderUid)
+	} else {
+		d.Set("folder", dashboard.Folder)
+	}
+	d.Set("url", dashboardURL(c.baseURL, uid, dashboard.Meta.Slug))
+	d.Set("version", int64(version))
+	d.Set("config_sha256", dashboardConfigSHA256(configJSON))
+	if len(tags) > 0 {
+		d.Set("tags", dashboard.Model["tags"])
+	}
+	d.Set("folder_title", folderTitle)
 
 	return nil
 }
 
 func UpdateDashboard(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client)
 
-	dashboard := gapi.Dashboard{}
+	// Saving the dashboard for nothing would add a version.
+	if !hasDashboardContentChange(d) && !d.HasChange("message") {
+		return ReadDashboard(d, meta)
+	}
+
+	dashboard := dashboardSaveRequest{}
 
-	dashboard.Model = prepareDashboardModel(d.Get("config_json").(string))
+	model, err := prepareDashboardModel(dashboardConfigJSON(d), d)
+	if err != nil {
+		return err
+	}
+	dashboard.Model = model
 
-	dashboard.Folder = int64(d.Get("folder").(int))
+	dashboard.Folder, err = client.dashboardFolderId(d)
+	if err != nil {
+		return err
+	}
+	// Grafana looks up a dashboard saved without its id by its title in the
+	// folder it's saved in, so renaming or moving it would save a copy
+	// instead.
+	current, err := client.gapi.Dashboard(d.Id())
+	if err != nil {
+		return err
+	}
+	model["id"] = current.Model["id"]
+	model["uid"] = current.Model["uid"]
 	dashboard.Overwrite = true
+	dashboard.Message = d.Get("message").(string)
 
-	resp, err := client.NewDashboard(dashboard)
+	resp := gapi.DashboardSaveResponse{}
+	err = client.request("POST", "/api/dashboards/db", nil, dashboard, &resp)
 	if err != nil {
 		return err
 	}
@@ -113,13 +321,79 @@ func UpdateDashboard(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteDashboard(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
 
 	slug := d.Id()
-	return client.DeleteDashboard(slug)
+	err := c.gapi.DeleteDashboard(slug)
+	if err == nil || err.Error() != "404 Not Found" {
+		return err
+	}
+
+	// Grafana 8 dropped the slug endpoints, so look up the dashboard's uid
+	// by its slug and delete it by uid instead.
+	title, _ := configMapOf(dashboardConfigJSON(d))["title"].(string)
+	uid, lookupErr := c.dashboardUIDBySlug(slug, title)
+	if lookupErr != nil {
+		return lookupErr
+	}
+	if uid == "" {
+		return err
+	}
+	return c.request("DELETE", "/api/dashboards/uid/"+uid, nil, nil, nil)
 }
 
-func prepareDashboardModel(configJSON string) map[string]interface{} {
+// dashboardUIDBySlug searches for the dashboard with the given slug and
+// returns its uid, or an empty string when there is none. The search is
+// narrowed down to the dashboard's title, or the words of its slug when the
+// title isn't known, as Grafana only returns a limited number of results.
+func (c *client) dashboardUIDBySlug(slug, title string) (string, error) {
+	if title == "" {
+		title = strings.Replace(slug, "-", " ", -1)
+	}
+	results, err := searchDashboards(c, url.Values{"type": {"dash-db"}, "query": {title}})
+	if err != nil {
+		return "", err
+	}
+	for _, r := range results {
+		if r.Uri == "db/"+slug || path.Base(r.Url) == slug {
+			return r.Uid, nil
+		}
+	}
+	return "", nil
+}
+
+// dashboardFolderId returns the id of the folder to save a dashboard in,
+// resolving its folder_uid when given. Dashboards are saved by folder id as
+// Grafana only accepts a folder uid from 8.0 on.
+func (c *client) dashboardFolderId(d *schema.ResourceData) (int64, error) {
+	uid := d.Get("folder_uid").(string)
+	if uid == "" {
+		return int64(d.Get("folder").(int)), nil
+	}
+	folder := gapi.Folder{}
+	err := c.request("GET", "/api/folders/"+url.PathEscape(uid), nil, nil, &folder)
+	if err != nil && err.Error() == "404 Not Found" {
+		return 0, fmt.Errorf("Error: A Grafana folder with the uid '%s' does not exist.", uid)
+	}
+	return folder.Id, err
+}
+
+// dashboardURL builds the link to a dashboard from the provider's base URL,
+// keeping any subpath Grafana is served from.
+func dashboardURL(base url.URL, uid, slug string) string {
+	base.User = nil
+	if uid == "" {
+		// Grafana before 5.0 has no dashboard uids.
+		base.Path = path.Join(base.Path, "dashboard/db", slug)
+	} else {
+		base.Path = path.Join(base.Path, "d", uid, slug)
+	}
+	return base.String()
+}
+
+// prepareDashboardModel returns the dashboard to save from its JSON and the
+// resource's inputs, strip_dashboard_alerts, datasource_uid_map and tags.
+func prepareDashboardModel(configJSON string, d *schema.ResourceData) (map[string]interface{}, error) {
 	configMap := map[string]interface{}{}
 	err := json.Unmarshal([]byte(configJSON), &configMap)
 	if err != nil {
@@ -127,24 +401,344 @@ func prepareDashboardModel(configJSON string) map[string]interface{} {
 		panic(fmt.Errorf("Invalid JSON got into prepare func"))
 	}
 
+	if err := substituteDashboardInputs(configMap, d.Get("inputs").(map[string]interface{})); err != nil {
+		return nil, err
+	}
+
 	delete(configMap, "id")
 	// Only exists in 5.0+
 	delete(configMap, "uid")
 	configMap["version"] = 0
+	deleteDashboardFolderRefs(configMap)
+	if d.Get("strip_dashboard_alerts").(bool) {
+		stripDashboardAlerts(configMap)
+	}
+	mapDashboardDatasourceUIDs(configMap, d.Get("datasource_uid_map").(map[string]interface{}))
+	// The tags attribute takes precedence over the tags of the JSON.
+	if tags := d.Get("tags").([]interface{}); len(tags) > 0 {
+		configMap["tags"] = tags
+	}
+
+	return configMap, nil
+}
+
+// mapDashboardDatasourceUIDs replaces the uids of the datasources referenced
+// anywhere in the dashboard, e.g. by panels, queries, annotations and template
+// variables, with the uid they're mapped to.
+func mapDashboardDatasourceUIDs(v interface{}, uids map[string]interface{}) {
+	if len(uids) == 0 {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(map[string]interface{}); ok && key == "datasource" {
+				uid, _ := ref["uid"].(string)
+				if mapped, ok := uids[uid].(string); ok {
+					ref["uid"] = mapped
+				}
+			}
+			mapDashboardDatasourceUIDs(value, uids)
+		}
+	case []interface{}:
+		for _, value := range v {
+			mapDashboardDatasourceUIDs(value, uids)
+		}
+	}
+}
+
+// stripDashboardAlerts removes the legacy alerts defined on the panels of a
+// dashboard, including the panels of collapsed rows and of pre-5.0 rows.
+func stripDashboardAlerts(configMap map[string]interface{}) {
+	var strip func(panels interface{})
+	strip = func(panels interface{}) {
+		list, _ := panels.([]interface{})
+		for _, p := range list {
+			panel, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			delete(panel, "alert")
+			strip(panel["panels"])
+		}
+	}
+	strip(configMap["panels"])
+	rows, _ := configMap["rows"].([]interface{})
+	for _, r := range rows {
+		if row, ok := r.(map[string]interface{}); ok {
+			strip(row["panels"])
+		}
+	}
+}
+
+// deleteDashboardFolderRefs removes the folder references dashboards exported
+// along with their metadata may embed. The folder or folder_uid attribute
+// decides where the dashboard is saved.
+func deleteDashboardFolderRefs(configMap map[string]interface{}) {
+	delete(configMap, "folderId")
+	delete(configMap, "folderUid")
+	meta, ok := configMap["meta"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, key := range []string{"folderId", "folderUid", "folderTitle", "folderUrl"} {
+		delete(meta, key)
+	}
+	if len(meta) == 0 {
+		delete(configMap, "meta")
+	}
+}
+
+// hasDashboardContentChange reports whether any of the attributes the saved
+// dashboard is made of changed, including the folder it's saved in.
+func hasDashboardContentChange(d interface{ HasChange(string) bool }) bool {
+	for _, key := range []string{"config_json", "config_yaml", "inputs", "strip_dashboard_alerts", "datasource_uid_map", "tags", "folder", "folder_uid"} {
+		if d.HasChange(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomizeDashboardDiff fails the plan when the dashboard isn't shaped like
+// a Grafana dashboard, refreshes more often than the provider's
+// min_refresh_interval allows or declares inputs that aren't given a value.
+// It also drops changes to the message alone, as saving the dashboard only to
+// record a new message would add a version that is otherwise identical to the
+// previous one.
+func CustomizeDashboardDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChange("message") && !hasDashboardContentChange(d) {
+		if err := d.Clear("message"); err != nil {
+			return err
+		}
+	}
 
+	// config_json is computed from config_yaml when that's set, so it's
+	// unknown until the dashboard is saved.
+	configKey := "config_json"
+	if !d.NewValueKnown("config_yaml") || d.Get("config_yaml").(string) != "" {
+		configKey = "config_yaml"
+	}
+	if !d.NewValueKnown(configKey) || !d.NewValueKnown("inputs") {
+		return nil
+	}
+	configJSON := dashboardConfigJSON(d)
+	if configJSON == "" {
+		return errors.New("Error: One of config_json or config_yaml must be set.")
+	}
+	configMap := configMapOf(configJSON)
+	if err := validateDashboardModel(configMap); err != nil {
+		return err
+	}
+	if c, ok := meta.(*client); ok && c.minRefreshInterval > 0 {
+		if err := validateDashboardRefresh(configMap, c.minRefreshInterval); err != nil {
+			return err
+		}
+	}
+	return substituteDashboardInputs(configMap, d.Get("inputs").(map[string]interface{}))
+}
+
+// validateDashboardRefresh checks that the dashboard doesn't refresh more
+// often than every min. Dashboards that don't refresh have an empty or false
+// refresh.
+func validateDashboardRefresh(configMap map[string]interface{}, min time.Duration) error {
+	refresh, ok := configMap["refresh"].(string)
+	if !ok || refresh == "" {
+		return nil
+	}
+	interval, err := parseInterval(refresh)
+	if err != nil {
+		return fmt.Errorf("Error: The dashboard refresh %s", err)
+	}
+	if interval < min {
+		return fmt.Errorf("Error: The dashboard refreshes every %s, more often than the minimum of %s.", refresh, min)
+	}
+	return nil
+}
+
+func configMapOf(configJSON string) map[string]interface{} {
+	configMap := map[string]interface{}{}
+	json.Unmarshal([]byte(configJSON), &configMap)
 	return configMap
 }
 
+var dashboardInputPattern = regexp.MustCompile(`\$\{(.+?)\}`)
+
+// substituteDashboardInputs replaces the ${NAME} placeholders of the inputs
+// declared in "__inputs" by dashboards exported for sharing externally, like
+// Grafana's import does. Inputs without a default value must be given one.
+// Other ${...} expressions, such as template variables, are left alone.
+func substituteDashboardInputs(configMap map[string]interface{}, inputs map[string]interface{}) error {
+	declared, ok := configMap["__inputs"].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make(map[string]string)
+	var missing []string
+	for _, i := range declared {
+		input, _ := i.(map[string]interface{})
+		name, _ := input["name"].(string)
+		if value, ok := inputs[name].(string); ok {
+			values[name] = value
+		} else if value, ok := input["value"].(string); ok && value != "" {
+			values[name] = value
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("Error: The dashboard requires values for the following inputs: %s", strings.Join(missing, ", "))
+	}
+
+	delete(configMap, "__inputs")
+	for k, v := range configMap {
+		configMap[k] = substituteDashboardInputValues(v, values)
+	}
+	return nil
+}
+
+func substituteDashboardInputValues(v interface{}, values map[string]string) interface{} {
+	switch v := v.(type) {
+	case string:
+		return dashboardInputPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			if value, ok := values[placeholder[2:len(placeholder)-1]]; ok {
+				return value
+			}
+			return placeholder
+		})
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = substituteDashboardInputValues(e, values)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = substituteDashboardInputValues(e, values)
+		}
+	}
+	return v
+}
+
+// validateDashboardModel checks the structure of a dashboard: it must have a
+// title, and the panels, rows and tags Grafana expects as lists must be
+// lists. The error names the offending key.
+func validateDashboardModel(configMap map[string]interface{}) error {
+	if title, ok := configMap["title"].(string); !ok || title == "" {
+		return fmt.Errorf("Error: Invalid dashboard: \"title\" must be a non-empty string")
+	}
+	for _, key := range []string{"panels", "rows"} {
+		v, ok := configMap[key]
+		if !ok {
+			continue
+		}
+		list, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("Error: Invalid dashboard: %q must be a list", key)
+		}
+		for i, e := range list {
+			if _, ok := e.(map[string]interface{}); !ok {
+				return fmt.Errorf("Error: Invalid dashboard: \"%s[%d]\" must be an object", key, i)
+			}
+		}
+	}
+	if v, ok := configMap["tags"]; ok {
+		tags, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("Error: Invalid dashboard: \"tags\" must be a list")
+		}
+		for i, tag := range tags {
+			if _, ok := tag.(string); !ok {
+				return fmt.Errorf("Error: Invalid dashboard: \"tags[%d]\" must be a string", i)
+			}
+		}
+	}
+	return nil
+}
+
+// dashboardConfigJSON returns the dashboard JSON of the resource, converted
+// from config_yaml when it's set.
+func dashboardConfigJSON(d interface{ Get(string) interface{} }) string {
+	if configYAML := d.Get("config_yaml").(string); configYAML != "" {
+		configJSON, _ := dashboardYAMLToJSON(configYAML)
+		return configJSON
+	}
+	return d.Get("config_json").(string)
+}
+
+// dashboardYAMLToJSON converts a dashboard written in YAML to JSON. JSON is
+// valid YAML as well, so JSON dashboards convert to themselves.
+func dashboardYAMLToJSON(configYAML string) (string, error) {
+	ty, err := ctyyaml.Standard.ImpliedType([]byte(configYAML))
+	if err != nil {
+		return "", err
+	}
+	if !ty.IsObjectType() {
+		return "", errors.New("the dashboard must be a mapping")
+	}
+	value, err := ctyyaml.Standard.Unmarshal([]byte(configYAML), ty)
+	if err != nil {
+		return "", err
+	}
+	configJSON, err := ctyjson.Marshal(value, ty)
+	if err != nil {
+		return "", err
+	}
+	return string(configJSON), nil
+}
+
+// NormalizeDashboardConfigYAML stores dashboards written in YAML as their
+// normalized JSON, so that only changes to their content show up.
+func NormalizeDashboardConfigYAML(configI interface{}) string {
+	configJSON, err := dashboardYAMLToJSON(configI.(string))
+	if err != nil {
+		// The validate function should've taken care of this.
+		return ""
+	}
+	return NormalizeDashboardConfigJSON(configJSON)
+}
+
+func ValidateDashboardConfigYAML(configI interface{}, k string) ([]string, []error) {
+	if _, err := dashboardYAMLToJSON(configI.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: invalid YAML: %s", k, err)}
+	}
+	return nil, nil
+}
+
 func ValidateDashboardConfigJSON(configI interface{}, k string) ([]string, []error) {
 	configJSON := configI.(string)
 	configMap := map[string]interface{}{}
 	err := json.Unmarshal([]byte(configJSON), &configMap)
+	if syntaxErr, ok := err.(*json.SyntaxError); ok {
+		line, column := jsonPosition(configJSON, syntaxErr.Offset)
+		return nil, []error{fmt.Errorf("%s: invalid JSON at line %d, column %d: %s", k, line, column, err)}
+	}
 	if err != nil {
 		return nil, []error{err}
 	}
 	return nil, nil
 }
 
+// jsonPosition returns the 1-based line and column of the last character
+// read by the JSON decoder when it failed after offset bytes.
+func jsonPosition(s string, offset int64) (int, int) {
+	if offset > int64(len(s)) {
+		offset = int64(len(s))
+	}
+	if offset > 0 {
+		offset--
+	}
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := len(before) - strings.LastIndex(before, "\n")
+	return line, column
+}
+
+// dashboardConfigSHA256 returns the hex encoded SHA-256 of the normalized
+// dashboard JSON, which only changes along with the dashboard content.
+func dashboardConfigSHA256(configJSON string) string {
+	sum := sha256.Sum256([]byte(NormalizeDashboardConfigJSON(configJSON)))
+	return hex.EncodeToString(sum[:])
+}
+
 func NormalizeDashboardConfigJSON(configI interface{}) string {
 	configJSON := configI.(string)
 
@@ -161,6 +755,7 @@ func NormalizeDashboardConfigJSON(configI interface{}) string {
 	delete(configMap, "version")
 	// Only exists in 5.0+
 	delete(configMap, "uid")
+	deleteDashboardFolderRefs(configMap)
 
 	ret, err := json.Marshal(configMap)
 	if err != nil {
@@ -1,13 +1,23 @@
 package grafana
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	gapi "github.com/nytm/go-grafana-api"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -30,6 +40,15 @@ func TestAccDashboard_basic(t *testing.T) {
 					resource.TestMatchResourceAttr(
 						"grafana_dashboard.test", "config_json", regexp.MustCompile(".*Terraform Acceptance Test.*"),
 					),
+					resource.TestMatchResourceAttr(
+						"grafana_dashboard.test", "url", regexp.MustCompile(`/d/[^/]+/terraform-acceptance-test$`),
+					),
+					resource.TestCheckResourceAttr(
+						"grafana_dashboard.test", "version", "1",
+					),
+					resource.TestCheckResourceAttr(
+						"grafana_dashboard.test", "folder_title", "General",
+					),
 				),
 			},
 			// second step updates it with a new title
@@ -40,6 +59,9 @@ func TestAccDashboard_basic(t *testing.T) {
 					resource.TestMatchResourceAttr(
 						"grafana_dashboard.test", "config_json", regexp.MustCompile(".*Updated Title.*"),
 					),
+					resource.TestCheckResourceAttr(
+						"grafana_dashboard.test", "version", "2",
+					),
 				),
 			},
 			// final step checks importing the current state we reached in the step above
@@ -52,6 +74,78 @@ func TestAccDashboard_basic(t *testing.T) {
 	})
 }
 
+func TestAccDashboard_noopUpdate(t *testing.T) {
+	var dashboard gapi.Dashboard
+	var sha string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardCheckDestroy(&dashboard),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardConfig_noop,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckVersion(&dashboard, 1),
+					testAccDashboardCheckSHA256("grafana_dashboard.test", &sha),
+				),
+			},
+			// The same dashboard formatted differently must not be saved
+			// again, which would add a version.
+			{
+				Config: testAccDashboardConfig_noopReformatted,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckVersion(&dashboard, 1),
+					resource.TestCheckResourceAttr(
+						"grafana_dashboard.test", "version", "1",
+					),
+					testAccDashboardCheckSHA256("grafana_dashboard.test", &sha),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboard_adoptExisting(t *testing.T) {
+	var dashboard gapi.Dashboard
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardCheckDestroy(&dashboard),
+		Steps: []resource.TestStep{
+			{
+				// The provider isn't configured before the first step, so
+				// the existing dashboard is made with a client of its own.
+				PreConfig: func() {
+					c, err := newClient(os.Getenv("GRAFANA_AUTH"), os.Getenv("GRAFANA_URL"))
+					if err != nil {
+						t.Fatal(err)
+					}
+					_, err = c.gapi.NewDashboard(gapi.Dashboard{
+						Model: map[string]interface{}{"title": "Terraform Adopt Test"},
+					})
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testAccDashboardConfig_adoptExisting(false),
+				ExpectError: regexp.MustCompile("title 'Terraform Adopt Test' already exists"),
+			},
+			{
+				Config: testAccDashboardConfig_adoptExisting(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckModel(&dashboard, "title", "Terraform Adopt Test"),
+					testAccDashboardCheckVersion(&dashboard, 2),
+				),
+			},
+		},
+	})
+}
+
 func TestAccDashboard_folder(t *testing.T) {
 	var dashboard gapi.Dashboard
 	var folder gapi.Folder
@@ -73,6 +167,85 @@ func TestAccDashboard_folder(t *testing.T) {
 					resource.TestMatchResourceAttr(
 						"grafana_dashboard.test_folder", "folder", regexp.MustCompile(`\d+`),
 					),
+					resource.TestCheckResourceAttr(
+						"grafana_dashboard.test_folder", "folder_title", "Terraform Folder Test Folder",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboard_staleFolderRef(t *testing.T) {
+	var dashboard gapi.Dashboard
+	var folder gapi.Folder
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardFolderCheckDestroy(&dashboard, &folder),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardConfig_staleFolderRef,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test_folder", &dashboard),
+					testAccFolderCheckExists("grafana_folder.test_folder", &folder),
+					testAccDashboardCheckExistsInFolder(&dashboard, &folder),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboard_folderUid(t *testing.T) {
+	var dashboard gapi.Dashboard
+	var folder gapi.Folder
+	var dashboardId interface{}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardFolderCheckDestroy(&dashboard, &folder),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardConfig_folderUid,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test_folder", &dashboard),
+					testAccFolderCheckExists("grafana_folder.test_folder", &folder),
+					testAccDashboardCheckExistsInFolder(&dashboard, &folder),
+					resource.TestCheckResourceAttrPair(
+						"grafana_dashboard.test_folder", "folder_uid", "grafana_folder.test_folder", "uid",
+					),
+					resource.TestCheckResourceAttr(
+						"grafana_dashboard.test_folder", "folder_title", "Terraform Folder Uid Test Folder",
+					),
+					func(s *terraform.State) error {
+						dashboardId = dashboard.Model["id"]
+						return nil
+					},
+				),
+			},
+			// Moving the dashboard to a folder created in the same apply
+			// resolves the new folder's uid once it exists.
+			{
+				Config: testAccDashboardConfig_folderUidMoved,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test_folder", &dashboard),
+					testAccFolderCheckExists("grafana_folder.test_folder_moved", &folder),
+					testAccDashboardCheckExistsInFolder(&dashboard, &folder),
+					resource.TestCheckResourceAttrPair(
+						"grafana_dashboard.test_folder", "folder_uid", "grafana_folder.test_folder_moved", "uid",
+					),
+					resource.TestCheckResourceAttr(
+						"grafana_dashboard.test_folder", "folder_title", "Terraform Folder Uid Moved Folder",
+					),
+					// Moved rather than recreated.
+					func(s *terraform.State) error {
+						if dashboard.Model["id"] != dashboardId {
+							return fmt.Errorf("expected the dashboard to keep the id %v, got %v", dashboardId, dashboard.Model["id"])
+						}
+						return nil
+					},
 				),
 			},
 		},
@@ -99,6 +272,788 @@ func TestAccDashboard_disappear(t *testing.T) {
 	})
 }
 
+func TestAccDashboard_inputs(t *testing.T) {
+	var dashboard gapi.Dashboard
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardCheckDestroy(&dashboard),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardConfig_inputs,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckModel(&dashboard, "description", "Reads from prometheus-prod"),
+				),
+			},
+			{
+				Config:      testAccDashboardConfig_inputsMissing,
+				ExpectError: regexp.MustCompile("requires values for the following inputs: DS_PROMETHEUS"),
+			},
+		},
+	})
+}
+
+func TestAccDashboard_tags(t *testing.T) {
+	var dashboard gapi.Dashboard
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardCheckDestroy(&dashboard),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardConfig_tags(`"team-a", "prod"`, `"from-json"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckTags(&dashboard, "team-a", "prod"),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "tags.#", "2"),
+				),
+			},
+			{
+				Config: testAccDashboardConfig_tags(`"team-a"`, `"from-json"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckTags(&dashboard, "team-a"),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "tags.#", "1"),
+				),
+			},
+			// Removing the attribute clears its tags, leaving those of the JSON.
+			{
+				Config: testAccDashboardConfig_tags("", `"from-json"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckTags(&dashboard, "from-json"),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "tags.#", "0"),
+				),
+			},
+			{
+				Config: testAccDashboardConfig_tags("", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckTags(&dashboard),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "tags.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboard_yaml(t *testing.T) {
+	var dashboard gapi.Dashboard
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardCheckDestroy(&dashboard),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardConfig_yaml,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					testAccDashboardCheckTags(&dashboard, "terraform", "yaml"),
+					resource.TestMatchResourceAttr(
+						"grafana_dashboard.test", "config_json", regexp.MustCompile(".*Terraform YAML Test.*"),
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDashboard_message(t *testing.T) {
+	var dashboard gapi.Dashboard
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDashboardCheckDestroy(&dashboard),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDashboardConfig_message("First", "abc123"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDashboardCheckExists("grafana_dashboard.test", &dashboard),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "message", "abc123"),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "version", "1"),
+				),
+			},
+			// A new message alone doesn't save the dashboard again.
+			{
+				Config: testAccDashboardConfig_message("First", "def456"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "message", "abc123"),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "version", "1"),
+				),
+			},
+			// The message is sent along with the next change of the dashboard.
+			{
+				Config: testAccDashboardConfig_message("Second", "def456"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "message", "def456"),
+					resource.TestCheckResourceAttr("grafana_dashboard.test", "version", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestSubstituteDashboardInputs(t *testing.T) {
+	configMap := configMapOf(`{
+		"__inputs": [
+			{"name": "DS_PROMETHEUS", "type": "datasource", "pluginId": "prometheus"},
+			{"name": "VAR_ENV", "type": "constant", "value": "staging"}
+		],
+		"title": "${VAR_ENV} overview",
+		"panels": [
+			{"datasource": "${DS_PROMETHEUS}", "targets": [{"expr": "up{instance=\"${instance}\"}"}]}
+		]
+	}`)
+
+	err := substituteDashboardInputs(configMap, map[string]interface{}{"DS_PROMETHEUS": "prometheus-prod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := configMap["__inputs"]; ok {
+		t.Errorf("expected __inputs to be removed")
+	}
+	if configMap["title"] != "staging overview" {
+		t.Errorf("expected the constant input's default to be used, got %q", configMap["title"])
+	}
+	panel := configMap["panels"].([]interface{})[0].(map[string]interface{})
+	if panel["datasource"] != "prometheus-prod" {
+		t.Errorf("expected the datasource input to be substituted, got %q", panel["datasource"])
+	}
+	target := panel["targets"].([]interface{})[0].(map[string]interface{})
+	if target["expr"] != `up{instance="${instance}"}` {
+		t.Errorf("expected template variables to be left alone, got %q", target["expr"])
+	}
+}
+
+func TestSubstituteDashboardInputs_missing(t *testing.T) {
+	configMap := configMapOf(`{
+		"__inputs": [
+			{"name": "DS_PROMETHEUS", "type": "datasource"},
+			{"name": "DS_LOKI", "type": "datasource"}
+		]
+	}`)
+
+	err := substituteDashboardInputs(configMap, map[string]interface{}{})
+	if err == nil || !strings.Contains(err.Error(), "DS_PROMETHEUS, DS_LOKI") {
+		t.Fatalf("expected an error listing both missing inputs, got %v", err)
+	}
+}
+
+func TestValidateDashboardModel(t *testing.T) {
+	cases := []struct {
+		config, err string
+	}{
+		{`{"title": "Test"}`, ""},
+		{`{"title": "Test", "panels": [{"type": "graph"}], "tags": ["a"]}`, ""},
+		{`{"panels": []}`, `"title" must be a non-empty string`},
+		{`{"title": ""}`, `"title" must be a non-empty string`},
+		{`{"title": "Test", "panels": {}}`, `"panels" must be a list`},
+		{`{"title": "Test", "rows": [{}, 1]}`, `"rows[1]" must be an object`},
+		{`{"title": "Test", "tags": ["a", 2]}`, `"tags[1]" must be a string`},
+	}
+	for _, c := range cases {
+		err := validateDashboardModel(configMapOf(c.config))
+		if c.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %s", c.config, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s: expected error containing %q, got %v", c.config, c.err, err)
+		}
+	}
+}
+
+func TestValidateDashboardRefresh(t *testing.T) {
+	cases := []struct {
+		config, err string
+	}{
+		{`{"title": "Test"}`, ""},
+		{`{"title": "Test", "refresh": ""}`, ""},
+		{`{"title": "Test", "refresh": false}`, ""},
+		{`{"title": "Test", "refresh": "1m"}`, ""},
+		{`{"title": "Test", "refresh": "1h"}`, ""},
+		{`{"title": "Test", "refresh": "10s"}`, "refreshes every 10s, more often than the minimum of 1m0s"},
+		{`{"title": "Test", "refresh": "often"}`, "not a valid interval"},
+	}
+	for _, c := range cases {
+		err := validateDashboardRefresh(configMapOf(c.config), time.Minute)
+		if c.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %s", c.config, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%s: expected error containing %q, got %v", c.config, c.err, err)
+		}
+	}
+}
+
+func TestValidateDashboardConfigJSON_position(t *testing.T) {
+	_, errs := ValidateDashboardConfigJSON("{\n  \"title\": \"Test\",\n  \"panels\": [}\n}", "config_json")
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "line 3, column 14") {
+		t.Fatalf("expected an error at line 3, column 14, got %v", errs)
+	}
+}
+
+func TestNormalizeDashboardConfigJSON(t *testing.T) {
+	expected := NormalizeDashboardConfigJSON(`{"title": "Test", "panels": [{"id": 1, "type": "graph"}]}`)
+	equivalent := []string{
+		"{\n  \"panels\": [{\"type\": \"graph\", \"id\": 1}],\n  \"title\": \"Test\"\n}\n",
+		`{"id": 3, "uid": "abc", "version": 12, "title": "Test", "panels": [{"id": 1, "type": "graph"}]}`,
+		`{"title": "Test", "folderId": 4, "folderUid": "ops", "meta": {"folderId": 4, "folderTitle": "Ops"}, "panels": [{"id": 1, "type": "graph"}]}`,
+	}
+	for _, config := range equivalent {
+		if got := NormalizeDashboardConfigJSON(config); got != expected {
+			t.Errorf("expected %s to normalize to %s, got %s", config, expected, got)
+		}
+	}
+}
+
+func TestNormalizeDashboardConfigYAML(t *testing.T) {
+	configJSON := `{"title": "Test", "editable": true, "refresh": "1m", "panels": [{"id": 1, "type": "graph", "gridPos": {"h": 8, "w": 12}}]}`
+	configYAML := `
+title: Test
+editable: true
+refresh: 1m
+panels:
+  - id: 1
+    type: graph
+    gridPos: {h: 8, w: 12}
+`
+	expected := NormalizeDashboardConfigJSON(configJSON)
+	if got := NormalizeDashboardConfigYAML(configYAML); got != expected {
+		t.Errorf("expected the YAML dashboard to normalize to %s, got %s", expected, got)
+	}
+	// JSON is valid YAML too.
+	if got := NormalizeDashboardConfigYAML(configJSON); got != expected {
+		t.Errorf("expected the JSON dashboard to normalize to %s, got %s", expected, got)
+	}
+}
+
+func TestValidateDashboardConfigYAML(t *testing.T) {
+	cases := []struct {
+		config string
+		valid  bool
+	}{
+		{"title: Test\n", true},
+		{"title: [Test\n", false},
+		{"- title: Test\n", false},
+	}
+	for _, c := range cases {
+		_, errs := ValidateDashboardConfigYAML(c.config, "config_yaml")
+		if c.valid && len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", c.config, errs)
+		}
+		if !c.valid && len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", c.config)
+		}
+	}
+}
+
+func TestCustomizeDashboardDiff_yaml(t *testing.T) {
+	meta := &client{minRefreshInterval: time.Minute}
+	cases := []struct {
+		configYAML, err string
+	}{
+		{"title: Test\nrefresh: 5m\n", ""},
+		{"title: Test\nrefresh: 10s\n", "more often than the minimum"},
+		{"panels: []\n", `"title" must be a non-empty string`},
+		{"title: Test\n__inputs: [{name: DS_PROMETHEUS}]\n", "DS_PROMETHEUS"},
+	}
+	for _, c := range cases {
+		raw, err := config.NewRawConfig(map[string]interface{}{"config_yaml": c.configYAML})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Creating the dashboard, config_json is only known once it's saved.
+		_, err = ResourceDashboard().Diff(nil, terraform.NewResourceConfig(raw), meta)
+		if c.err == "" && err != nil {
+			t.Errorf("%q: unexpected error %s", c.configYAML, err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("%q: expected error containing %q, got %v", c.configYAML, c.err, err)
+		}
+	}
+}
+
+func TestDashboardConfigSHA256(t *testing.T) {
+	expected := dashboardConfigSHA256(`{"title": "Test", "panels": [{"id": 1, "type": "graph"}]}`)
+	// Grafana returns the stored dashboard with its id, uid and version.
+	stored := `{"id": 3, "uid": "abc", "version": 2, "panels": [{"type": "graph", "id": 1}], "title": "Test"}`
+	if got := dashboardConfigSHA256(stored); got != expected {
+		t.Errorf("expected the stored dashboard to hash to %s, got %s", expected, got)
+	}
+	if got := dashboardConfigSHA256(`{"title": "Other", "panels": [{"id": 1, "type": "graph"}]}`); got == expected {
+		t.Errorf("expected a different dashboard to hash differently")
+	}
+}
+
+func TestDashboardURL(t *testing.T) {
+	cases := []struct {
+		base, uid, slug, expected string
+	}{
+		{"http://grafana.example.com", "abc", "my-dash", "http://grafana.example.com/d/abc/my-dash"},
+		{"http://grafana.example.com/", "abc", "my-dash", "http://grafana.example.com/d/abc/my-dash"},
+		{"https://example.com/grafana/", "abc", "my-dash", "https://example.com/grafana/d/abc/my-dash"},
+		{"http://localhost:3000", "", "my-dash", "http://localhost:3000/dashboard/db/my-dash"},
+	}
+	for _, c := range cases {
+		base, err := url.Parse(c.base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := dashboardURL(*base, c.uid, c.slug); got != c.expected {
+			t.Errorf("dashboardURL(%q, %q, %q) = %q, expected %q", c.base, c.uid, c.slug, got, c.expected)
+		}
+	}
+}
+
+func TestDeleteDashboard_slugFallback(t *testing.T) {
+	deleted := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "DELETE" && r.URL.Path == "/api/dashboards/db/my-dash":
+			http.NotFound(w, r)
+		case r.Method == "GET" && r.URL.Path == "/api/search":
+			if got := r.URL.Query().Get("query"); got != "My Dash" {
+				t.Errorf("expected a search for the title My Dash, got %q", got)
+			}
+			// More dashboards match than fit in one page.
+			results := []map[string]string{}
+			if r.URL.Query().Get("page") == "1" {
+				for i := 0; i < dashboardsPageSize; i++ {
+					results = append(results, map[string]string{"uid": fmt.Sprintf("other-%d", i), "url": fmt.Sprintf("/d/other-%d/my-dash-copy", i)})
+				}
+			} else {
+				results = append(results, map[string]string{"uid": "abc", "url": "/d/abc/my-dash"})
+			}
+			json.NewEncoder(w).Encode(results)
+		case r.Method == "DELETE" && r.URL.Path == "/api/dashboards/uid/abc":
+			deleted = "abc"
+			fmt.Fprint(w, `{"title": "My Dash"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDashboard().Schema, map[string]interface{}{
+		"config_json": `{"title": "My Dash"}`,
+	})
+	d.SetId("my-dash")
+	if err := DeleteDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if deleted != "abc" {
+		t.Fatalf("expected dashboard abc to be deleted by uid, got %q", deleted)
+	}
+}
+
+func TestDashboardFolderId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/folders/ops":
+			fmt.Fprint(w, `{"id": 8, "uid": "ops", "title": "Ops"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		config   map[string]interface{}
+		expected int64
+	}{
+		{map[string]interface{}{}, 0},
+		{map[string]interface{}{"folder": 3}, 3},
+		{map[string]interface{}{"folder_uid": "ops"}, 8},
+	}
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, ResourceDashboard().Schema, tc.config)
+		id, err := c.dashboardFolderId(d)
+		if err != nil {
+			t.Fatalf("%v: %s", tc.config, err)
+		}
+		if id != tc.expected {
+			t.Errorf("%v: expected folder %d, got %d", tc.config, tc.expected, id)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDashboard().Schema, map[string]interface{}{"folder_uid": "missing"})
+	if _, err := c.dashboardFolderId(d); err == nil || !strings.Contains(err.Error(), "uid 'missing' does not exist") {
+		t.Fatalf("expected a missing folder error, got %v", err)
+	}
+}
+
+func TestCreateDashboard_nameExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/dashboards/db":
+			var body dashboardSaveRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			if !body.Overwrite {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				fmt.Fprint(w, `{"status": "name-exists", "message": "A dashboard with the same name in the folder already exists"}`)
+				return
+			}
+			fmt.Fprint(w, `{"slug": "existing", "status": "success", "version": 4}`)
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/db/existing":
+			fmt.Fprint(w, `{"meta": {"slug": "existing"}, "dashboard": {"title": "Existing", "uid": "abc", "version": 4}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDashboard().Schema, map[string]interface{}{
+		"config_json": `{"title": "Existing"}`,
+	})
+	err = CreateDashboard(d, c)
+	if err == nil || !strings.Contains(err.Error(), "title 'Existing' already exists") {
+		t.Fatalf("expected a name exists error, got %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, ResourceDashboard().Schema, map[string]interface{}{
+		"config_json":    `{"title": "Existing"}`,
+		"adopt_existing": true,
+	})
+	if err := CreateDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "existing" {
+		t.Errorf("expected the existing dashboard to be adopted, got id %q", d.Id())
+	}
+}
+
+func TestCreateDashboard_stripAlerts(t *testing.T) {
+	var saved map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/dashboards/db":
+			var body dashboardSaveRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			saved = body.Model
+			fmt.Fprint(w, `{"slug": "alerting", "status": "success", "version": 1}`)
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/db/alerting":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"meta":      map[string]interface{}{"slug": "alerting"},
+				"dashboard": saved,
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := `{
+		"title": "Alerting",
+		"panels": [
+			{"id": 1, "type": "graph", "alert": {"name": "High latency"}},
+			{"id": 2, "type": "row", "collapsed": true, "panels": [{"id": 3, "type": "graph", "alert": {"name": "Errors"}}]}
+		],
+		"rows": [{"panels": [{"id": 4, "type": "graph", "alert": {"name": "Legacy"}}]}]
+	}`
+	d := schema.TestResourceDataRaw(t, ResourceDashboard().Schema, map[string]interface{}{
+		"config_json":            config,
+		"strip_dashboard_alerts": true,
+	})
+	if err := CreateDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	savedJSON, _ := json.Marshal(saved)
+	if strings.Contains(string(savedJSON), `"alert"`) {
+		t.Errorf("expected the alerts to be stripped, saved %s", savedJSON)
+	}
+	// The stored dashboard lacks the stripped alerts, which mustn't show up
+	// as a change.
+	if got := d.Get("config_json").(string); got != config {
+		t.Errorf("expected config_json to be kept as configured, got %s", got)
+	}
+}
+
+func TestCreateDashboard_datasourceUIDMap(t *testing.T) {
+	var saved map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/dashboards/db":
+			var body dashboardSaveRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			saved = body.Model
+			fmt.Fprint(w, `{"slug": "latency", "status": "success", "version": 1}`)
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/db/latency":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"meta":      map[string]interface{}{"slug": "latency"},
+				"dashboard": saved,
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := `{
+		"title": "Latency",
+		"panels": [{
+			"id": 1, "type": "timeseries",
+			"datasource": {"type": "prometheus", "uid": "prom-dev"},
+			"targets": [{"refId": "A", "datasource": {"type": "loki", "uid": "loki-dev"}}]
+		}],
+		"templating": {"list": [{"name": "job", "datasource": {"type": "prometheus", "uid": "unmapped"}}]}
+	}`
+	d := schema.TestResourceDataRaw(t, ResourceDashboard().Schema, map[string]interface{}{
+		"config_json": config,
+		"datasource_uid_map": map[string]interface{}{
+			"prom-dev": "prom-prod",
+			"loki-dev": "loki-prod",
+		},
+	})
+	if err := CreateDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	panel := saved["panels"].([]interface{})[0].(map[string]interface{})
+	if uid := panel["datasource"].(map[string]interface{})["uid"]; uid != "prom-prod" {
+		t.Errorf("expected the panel datasource to be mapped to prom-prod, got %v", uid)
+	}
+	target := panel["targets"].([]interface{})[0].(map[string]interface{})
+	if uid := target["datasource"].(map[string]interface{})["uid"]; uid != "loki-prod" {
+		t.Errorf("expected the query datasource to be mapped to loki-prod, got %v", uid)
+	}
+	variable := saved["templating"].(map[string]interface{})["list"].([]interface{})[0].(map[string]interface{})
+	if uid := variable["datasource"].(map[string]interface{})["uid"]; uid != "unmapped" {
+		t.Errorf("expected the unmapped datasource to be left alone, got %v", uid)
+	}
+	// The stored dashboard references the mapped uids, which mustn't show up
+	// as a change.
+	if got := d.Get("config_json").(string); got != config {
+		t.Errorf("expected config_json to be kept as configured, got %s", got)
+	}
+}
+
+func TestReadDashboard_tags(t *testing.T) {
+	var saved map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/api/dashboards/db":
+			var body dashboardSaveRequest
+			json.NewDecoder(r.Body).Decode(&body)
+			saved = body.Model
+			fmt.Fprint(w, `{"slug": "tagged", "status": "success", "version": 1}`)
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/db/tagged":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"meta":      map[string]interface{}{"slug": "tagged"},
+				"dashboard": saved,
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := `{"title": "Tagged", "tags": ["from-json"]}`
+	d := schema.TestResourceDataRaw(t, ResourceDashboard().Schema, map[string]interface{}{
+		"config_json": config,
+		"tags":        []interface{}{"team-a", "prod"},
+	})
+	if err := CreateDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if tags := saved["tags"]; !reflect.DeepEqual(tags, []interface{}{"team-a", "prod"}) {
+		t.Errorf("expected the tags attribute to take precedence, saved %v", tags)
+	}
+	if got := d.Get("config_json").(string); got != config {
+		t.Errorf("expected config_json to be kept as configured, got %s", got)
+	}
+
+	// Tags changed in Grafana show up as a change.
+	saved["tags"] = []interface{}{"team-a"}
+	if err := ReadDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if tags := d.Get("tags"); !reflect.DeepEqual(tags, []interface{}{"team-a"}) {
+		t.Errorf("expected the tags to be read back, got %v", tags)
+	}
+
+	// Removing the attribute saves the dashboard with the tags of the JSON.
+	// The JSON read back with the changed tags is planned back too.
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"config_json": {Old: d.Get("config_json").(string), New: config},
+		"tags.#":      {Old: "1", New: "0"},
+		"tags.0":      {Old: "team-a", NewRemoved: true},
+	}}
+	d, err = schema.InternalMap(ResourceDashboard().Schema).Data(d.State(), diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if tags := saved["tags"]; !reflect.DeepEqual(tags, []interface{}{"from-json"}) {
+		t.Errorf("expected the tags of the JSON to be saved, saved %v", tags)
+	}
+	if tags := d.Get("tags").([]interface{}); len(tags) != 0 {
+		t.Errorf("expected no tags attribute, got %v", tags)
+	}
+}
+
+func TestUpdateDashboard_rename(t *testing.T) {
+	saved := dashboardSaveRequest{
+		Model: map[string]interface{}{"id": 4, "uid": "abc", "title": "Old Title", "version": 1},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/db/old-title":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"meta":      map[string]interface{}{"slug": "old-title"},
+				"dashboard": saved.Model,
+			})
+		case r.Method == "POST" && r.URL.Path == "/api/dashboards/db":
+			saved = dashboardSaveRequest{}
+			json.NewDecoder(r.Body).Decode(&saved)
+			fmt.Fprint(w, `{"slug": "new-title", "status": "success", "version": 2}`)
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/db/new-title":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"meta":      map[string]interface{}{"slug": "new-title"},
+				"dashboard": saved.Model,
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &terraform.InstanceState{ID: "old-title", Attributes: map[string]string{
+		"config_json": `{"title":"Old Title"}`,
+	}}
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"config_json": {Old: `{"title":"Old Title"}`, New: `{"title":"New Title"}`},
+	}}
+	d, err := schema.InternalMap(ResourceDashboard().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Saved by its id, so Grafana renames it rather than saving a copy under
+	// the new title.
+	if id, uid := saved.Model["id"], saved.Model["uid"]; id != float64(4) || uid != "abc" {
+		t.Errorf("expected the dashboard to be saved with its id and uid, saved %v and %v", id, uid)
+	}
+	if d.Id() != "new-title" {
+		t.Errorf("expected the id to follow the new slug, got %s", d.Id())
+	}
+}
+
+func TestUpdateDashboard_moveFolder(t *testing.T) {
+	saved := dashboardSaveRequest{
+		Model:  map[string]interface{}{"id": 4, "uid": "moved", "title": "Moved", "version": 1},
+		Folder: 3,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/folders/new":
+			fmt.Fprint(w, `{"id": 7, "uid": "new", "title": "New"}`)
+		case r.Method == "GET" && r.URL.Path == "/api/folders/id/7":
+			fmt.Fprint(w, `{"id": 7, "uid": "new", "title": "New"}`)
+		case r.Method == "GET" && r.URL.Path == "/api/folders/id/3":
+			fmt.Fprint(w, `{"id": 3, "uid": "old", "title": "Old"}`)
+		case r.Method == "POST" && r.URL.Path == "/api/dashboards/db":
+			saved = dashboardSaveRequest{}
+			json.NewDecoder(r.Body).Decode(&saved)
+			fmt.Fprint(w, `{"slug": "moved", "status": "success", "version": 2}`)
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/db/moved":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"meta":      map[string]interface{}{"slug": "moved", "folderId": saved.Folder},
+				"dashboard": saved.Model,
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing folder_uid alone updates the dashboard in place.
+	config := `{"title": "Moved"}`
+	state := &terraform.InstanceState{ID: "moved", Attributes: map[string]string{
+		"config_json": config,
+		"folder_uid":  "old",
+	}}
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"folder_uid": {Old: "old", New: "new"},
+	}}
+	if ResourceDashboard().Schema["folder_uid"].ForceNew {
+		t.Fatal("expected changing folder_uid not to recreate the dashboard")
+	}
+	d, err := schema.InternalMap(ResourceDashboard().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateDashboard(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if saved.Folder != 7 || !saved.Overwrite {
+		t.Errorf("expected the dashboard to be overwritten in folder 7, saved %+v", saved)
+	}
+	// Saved by its id, so it's moved rather than copied to the new folder.
+	if id, uid := saved.Model["id"], saved.Model["uid"]; id != float64(4) || uid != "moved" {
+		t.Errorf("expected the dashboard to be saved with its id and uid, saved %v and %v", id, uid)
+	}
+	if got := d.Get("folder_title"); got != "New" {
+		t.Errorf("expected folder_title to be New, got %v", got)
+	}
+}
+
 func testAccDashboardCheckExists(rn string, dashboard *gapi.Dashboard) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -110,7 +1065,7 @@ func testAccDashboardCheckExists(rn string, dashboard *gapi.Dashboard) resource.
 			return fmt.Errorf("resource id not set")
 		}
 
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		gotDashboard, err := client.Dashboard(rs.Primary.ID)
 		if err != nil {
 			return fmt.Errorf("error getting dashboard: %s", err)
@@ -131,11 +1086,64 @@ func testAccDashboardCheckExistsInFolder(dashboard *gapi.Dashboard, folder *gapi
 	}
 }
 
+func testAccDashboardCheckModel(dashboard *gapi.Dashboard, key, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if got := dashboard.Model[key]; got != expected {
+			return fmt.Errorf("dashboard %s is %#v, expected %q", key, got, expected)
+		}
+		return nil
+	}
+}
+
+func testAccDashboardCheckVersion(dashboard *gapi.Dashboard, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if got, _ := dashboard.Model["version"].(float64); int(got) != expected {
+			return fmt.Errorf("dashboard version is %v, expected %d", dashboard.Model["version"], expected)
+		}
+		return nil
+	}
+}
+
+// testAccDashboardCheckSHA256 records config_sha256 the first time and checks
+// it stays the same afterwards.
+func testAccDashboardCheckSHA256(rn string, sha *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+		got := rs.Primary.Attributes["config_sha256"]
+		if got == "" {
+			return fmt.Errorf("config_sha256 not set")
+		}
+		if *sha != "" && got != *sha {
+			return fmt.Errorf("config_sha256 changed from %s to %s", *sha, got)
+		}
+		*sha = got
+		return nil
+	}
+}
+
+func testAccDashboardCheckTags(dashboard *gapi.Dashboard, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		var tags []string
+		// Grafana leaves tags out of dashboards saved without them.
+		stored, _ := dashboard.Model["tags"].([]interface{})
+		for _, tag := range stored {
+			tags = append(tags, tag.(string))
+		}
+		if !reflect.DeepEqual(tags, expected) {
+			return fmt.Errorf("dashboard tags are %v, expected %v", tags, expected)
+		}
+		return nil
+	}
+}
+
 func testAccDashboardDisappear(dashboard *gapi.Dashboard) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		// At this point testAccDashboardCheckExists should have been called and
 		// dashboard should have been populated
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		client.DeleteDashboard((*dashboard).Meta.Slug)
 		return nil
 	}
@@ -143,7 +1151,7 @@ func testAccDashboardDisappear(dashboard *gapi.Dashboard) resource.TestCheckFunc
 
 func testAccDashboardCheckDestroy(dashboard *gapi.Dashboard) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		_, err := client.Dashboard(dashboard.Meta.Slug)
 		if err == nil {
 			return fmt.Errorf("dashboard still exists")
@@ -154,7 +1162,7 @@ func testAccDashboardCheckDestroy(dashboard *gapi.Dashboard) resource.TestCheckF
 
 func testAccDashboardFolderCheckDestroy(dashboard *gapi.Dashboard, folder *gapi.Folder) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		_, err := client.Dashboard(dashboard.Meta.Slug)
 		if err == nil {
 			return fmt.Errorf("dashboard still exists")
@@ -212,6 +1220,78 @@ EOT
 }
 `
 
+const testAccDashboardConfig_staleFolderRef = `
+resource "grafana_folder" "test_folder" {
+    title = "Terraform Stale Folder Ref Test Folder"
+}
+
+resource "grafana_dashboard" "test_folder" {
+    folder_uid = "${grafana_folder.test_folder.uid}"
+    config_json = <<EOT
+{
+    "title": "Terraform Stale Folder Ref Test Dashboard",
+    "folderId": 999999,
+    "folderUid": "stale",
+    "meta": {"folderId": 999999, "folderTitle": "Stale"}
+}
+EOT
+}
+`
+
+const testAccDashboardConfig_folderUid = `
+resource "grafana_folder" "test_folder" {
+    title = "Terraform Folder Uid Test Folder"
+}
+
+resource "grafana_dashboard" "test_folder" {
+    folder_uid = "${grafana_folder.test_folder.uid}"
+    config_json = <<EOT
+{
+    "title": "Terraform Folder Uid Test Dashboard"
+}
+EOT
+}
+`
+
+const testAccDashboardConfig_noop = `
+resource "grafana_dashboard" "test" {
+    config_json = <<EOT
+{
+    "title": "Terraform No-op Test",
+    "tags": ["terraform"],
+    "panels": [{"id": 1, "type": "graph", "title": "Requests"}]
+}
+EOT
+}
+`
+
+const testAccDashboardConfig_noopReformatted = `
+resource "grafana_dashboard" "test" {
+    config_json = <<EOT
+{"panels":[{"title":"Requests","type":"graph","id":1}],"tags":["terraform"],"title":"Terraform No-op Test","version":7}
+EOT
+}
+`
+
+const testAccDashboardConfig_folderUidMoved = `
+resource "grafana_folder" "test_folder" {
+    title = "Terraform Folder Uid Test Folder"
+}
+
+resource "grafana_folder" "test_folder_moved" {
+    title = "Terraform Folder Uid Moved Folder"
+}
+
+resource "grafana_dashboard" "test_folder" {
+    folder_uid = "${grafana_folder.test_folder_moved.uid}"
+    config_json = <<EOT
+{
+    "title": "Terraform Folder Uid Test Dashboard"
+}
+EOT
+}
+`
+
 const testAccDashboardConfig_disappear = `
 resource "grafana_dashboard" "test" {
     config_json = <<EOT
@@ -221,3 +1301,94 @@ resource "grafana_dashboard" "test" {
 EOT
 }
 `
+
+const testAccDashboardConfig_inputs = `
+resource "grafana_dashboard" "test" {
+    inputs = {
+        DS_PROMETHEUS = "prometheus-prod"
+    }
+    config_json = <<EOT
+{
+    "__inputs": [
+        {"name": "DS_PROMETHEUS", "label": "Prometheus", "type": "datasource", "pluginId": "prometheus"}
+    ],
+    "title": "Terraform Inputs Test",
+    "description": "Reads from $${DS_PROMETHEUS}"
+}
+EOT
+}
+`
+
+const testAccDashboardConfig_inputsMissing = `
+resource "grafana_dashboard" "test" {
+    config_json = <<EOT
+{
+    "__inputs": [
+        {"name": "DS_PROMETHEUS", "label": "Prometheus", "type": "datasource", "pluginId": "prometheus"}
+    ],
+    "title": "Terraform Inputs Test",
+    "description": "Reads from $${DS_PROMETHEUS}"
+}
+EOT
+}
+`
+
+func testAccDashboardConfig_adoptExisting(adopt bool) string {
+	return fmt.Sprintf(`
+resource "grafana_dashboard" "test" {
+    adopt_existing = %t
+    config_json = <<EOT
+{
+    "title": "Terraform Adopt Test"
+}
+EOT
+}
+`, adopt)
+}
+
+func testAccDashboardConfig_message(description, message string) string {
+	return fmt.Sprintf(`
+resource "grafana_dashboard" "test" {
+    message = "%s"
+    config_json = <<EOT
+{
+    "title": "Terraform Message Test",
+    "description": "%s"
+}
+EOT
+}
+`, message, description)
+}
+
+// testAccDashboardConfig_tags leaves the tags attribute out when tags is
+// empty.
+func testAccDashboardConfig_tags(tags, jsonTags string) string {
+	attr := ""
+	if tags != "" {
+		attr = fmt.Sprintf("tags = [%s]", tags)
+	}
+	return fmt.Sprintf(`
+resource "grafana_dashboard" "test" {
+    %s
+    config_json = <<EOT
+{
+    "title": "Terraform Tags Test",
+    "tags": [%s]
+}
+EOT
+}
+`, attr, jsonTags)
+}
+
+const testAccDashboardConfig_yaml = `
+resource "grafana_dashboard" "test" {
+    config_yaml = <<EOT
+title: Terraform YAML Test
+tags: [terraform, yaml]
+panels:
+  - id: 1
+    type: graph
+    title: Requests
+EOT
+}
+`
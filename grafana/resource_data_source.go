@@ -1,9 +1,15 @@
 package grafana
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform/helper/schema"
 
@@ -16,6 +22,10 @@ func ResourceDataSource() *schema.Resource {
 		Update: UpdateDataSource,
 		Delete: DeleteDataSource,
 		Read:   ReadDataSource,
+		Importer: &schema.ResourceImporter{
+			State: ImportDataSource,
+		},
+		CustomizeDiff: CustomizeDataSourceDiff,
 
 		Schema: map[string]*schema.Schema{
 			"type": {
@@ -28,6 +38,13 @@ func ResourceDataSource() *schema.Resource {
 				Required: true,
 			},
 
+			"uid": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: ValidateUID,
+			},
+
 			"url": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -115,6 +132,38 @@ func ResourceDataSource() *schema.Resource {
 				},
 			},
 
+			// Changing a keeper updates the datasource, which sends
+			// secure_json_data and http_headers again. Grafana never returns
+			// them, so changes to them can't be detected otherwise.
+			"secure_json_data_keepers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"http_headers": {
+				Type:      schema.TypeMap,
+				Optional:  true,
+				Sensitive: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			// Grafana only makes datasources provisioned from files read-only.
+			"read_only": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"test_on_create": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
 			"database_name": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -122,48 +171,120 @@ func ResourceDataSource() *schema.Resource {
 			},
 
 			"access_mode": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "proxy",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "proxy",
+				ValidateFunc: ValidateDataSourceAccessMode,
 			},
 		},
 	}
 }
 
+// httpHeaderNameKey matches the jsonData keys holding the names of the
+// custom HTTP headers sent to a datasource.
+var httpHeaderNameKey = regexp.MustCompile(`^httpHeaderName(\d+)$`)
+
+// dataSourceResponse is gapi.DataSource with the complete jsonData, of which
+// go-grafana-api only knows a fixed set of fields.
+type dataSourceResponse struct {
+	gapi.DataSource
+	Uid      string                 `json:"uid"`
+	JSONData map[string]interface{} `json:"jsonData"`
+	ReadOnly bool                   `json:"readOnly"`
+}
+
 // CreateDataSource creates a Grafana datasource
 func CreateDataSource(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
 
-	dataSource, err := makeDataSource(d)
+	dataSource, err := makeDataSourceBody(d, c)
 	if err != nil {
 		return err
 	}
 
-	id, err := client.NewDataSource(dataSource)
+	result := struct {
+		Id int64 `json:"id"`
+	}{}
+	err = c.request("POST", "/api/datasources", nil, dataSource, &result)
 	if err != nil {
 		return err
 	}
 
-	d.SetId(strconv.FormatInt(id, 10))
+	d.SetId(strconv.FormatInt(result.Id, 10))
+
+	if d.Get("test_on_create").(bool) {
+		if err := c.checkDataSourceHealth(result.Id); err != nil {
+			return err
+		}
+	}
 
 	return ReadDataSource(d, meta)
 }
 
 // UpdateDataSource updates a Grafana datasource
 func UpdateDataSource(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
 
-	dataSource, err := makeDataSource(d)
+	if d.Get("read_only").(bool) {
+		return fmt.Errorf("Error: The datasource '%s' is provisioned from a file and read-only, Terraform can't update it.", d.Get("name").(string))
+	}
+
+	dataSource, err := makeDataSourceBody(d, c)
+	if err != nil {
+		return err
+	}
+
+	err = c.request("PUT", "/api/datasources/"+d.Id(), nil, dataSource, nil)
 	if err != nil {
 		return err
 	}
 
-	return client.UpdateDataSource(dataSource)
+	if d.Get("test_on_create").(bool) {
+		id, _ := strconv.ParseInt(d.Id(), 10, 64)
+		return c.checkDataSourceHealth(id)
+	}
+	return nil
+}
+
+// defaultDataSourceClaim is the datasource saved as the default one by a
+// provider instance.
+type defaultDataSourceClaim struct {
+	mu   sync.Mutex
+	name string
+}
+
+// claimDefaultDataSource fails when another datasource of this provider was
+// already planned as the default one. Grafana only has one default datasource
+// and unsets the previous one, so two datasources configured as the default
+// would take the flag from each other on every apply.
+func (c *client) claimDefaultDataSource(name string) error {
+	c.defaultDataSource.mu.Lock()
+	defer c.defaultDataSource.mu.Unlock()
+	if claimed := c.defaultDataSource.name; claimed != "" && claimed != name {
+		return fmt.Errorf("Error: Both the datasources '%s' and '%s' are configured with is_default, Grafana only has one default datasource.", claimed, name)
+	}
+	c.defaultDataSource.name = name
+	return nil
+}
+
+// warnDefaultDataSourceTaken logs which datasource took the default flag of
+// the datasource name. Failing to find it doesn't fail the refresh.
+func (c *client) warnDefaultDataSourceTaken(name string) {
+	var dataSources []dataSourceResponse
+	if err := c.request("GET", "/api/datasources", nil, nil, &dataSources); err != nil {
+		log.Printf("[WARN] datasource %s is no longer the default one, listing datasources to find the new one failed: %s", name, err)
+		return
+	}
+	for _, ds := range dataSources {
+		if ds.IsDefault {
+			log.Printf("[WARN] datasource %s is no longer the default one, %s is. If both are configured with is_default, they take the flag from each other on every apply, set it on only one of them", name, ds.Name)
+		}
+	}
 }
 
 // ReadDataSource reads a Grafana datasource
 func ReadDataSource(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
 
 	idStr := d.Id()
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -171,7 +292,8 @@ func ReadDataSource(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Invalid id: %#v", idStr)
 	}
 
-	dataSource, err := client.DataSource(id)
+	dataSource := dataSourceResponse{}
+	err = c.request("GET", fmt.Sprintf("/api/datasources/%d", id), nil, nil, &dataSource)
 	if err != nil {
 		if err.Error() == "404 Not Found" {
 			log.Printf("[WARN] removing datasource %s from state because it no longer exists in grafana", d.Get("name").(string))
@@ -181,25 +303,64 @@ func ReadDataSource(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	if dataSource.ReadOnly {
+		log.Printf("[WARN] datasource %s is provisioned from a file and read-only, Terraform can't manage it", dataSource.Name)
+	}
+
 	d.Set("id", dataSource.Id)
+	d.Set("read_only", dataSource.ReadOnly)
 	d.Set("access_mode", dataSource.Access)
 	d.Set("basic_auth_enabled", dataSource.BasicAuth)
 	d.Set("basic_auth_username", dataSource.BasicAuthUser)
 	d.Set("basic_auth_password", dataSource.BasicAuthPassword)
-	d.Set("database_name", dataSource.Database)
+	d.Set("database_name", dataSourceDatabase(&dataSource))
+	// Another datasource was made the default one since the last run, which
+	// shows up as a change of is_default.
+	if d.Get("is_default").(bool) && !dataSource.IsDefault {
+		c.warnDefaultDataSourceTaken(dataSource.Name)
+	}
 	d.Set("is_default", dataSource.IsDefault)
 	d.Set("name", dataSource.Name)
+	d.Set("uid", dataSource.Uid)
 	d.Set("password", dataSource.Password)
 	d.Set("type", dataSource.Type)
 	d.Set("url", dataSource.URL)
 	d.Set("username", dataSource.User)
+	d.Set("http_headers", readHTTPHeaders(dataSource.JSONData, d.Get("http_headers").(map[string]interface{})))
 
 	return nil
 }
 
+// checkDataSourceHealth has Grafana test the connection to a datasource and
+// returns the reason it failed. Datasources without a backend can't be
+// tested by Grafana and are skipped.
+func (c *client) checkDataSourceHealth(id int64) error {
+	err := c.request("GET", fmt.Sprintf("/api/datasources/%d/health", id), nil, nil, nil)
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		return err
+	}
+	if strings.HasPrefix(apiErr.status, "404") || strings.HasPrefix(apiErr.status, "501") {
+		log.Printf("[WARN] skipping the connection test of datasource %d as Grafana can't test it: %s", id, apiErr.status)
+		return nil
+	}
+	result := struct {
+		Message string `json:"message"`
+	}{}
+	json.Unmarshal(apiErr.body, &result)
+	if result.Message == "" {
+		result.Message = apiErr.status
+	}
+	return fmt.Errorf("Error: Grafana can't connect to datasource %d: %s", id, result.Message)
+}
+
 // DeleteDataSource deletes a Grafana datasource
 func DeleteDataSource(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
+
+	if d.Get("read_only").(bool) {
+		return fmt.Errorf("Error: The datasource '%s' is provisioned from a file and read-only, Terraform can't delete it. Remove it from the state instead.", d.Get("name").(string))
+	}
 
 	idStr := d.Id()
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -207,7 +368,112 @@ func DeleteDataSource(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("Invalid id: %#v", idStr)
 	}
 
-	return client.DeleteDataSource(id)
+	err = c.gapi.DeleteDataSource(id)
+	if err == nil || err.Error() != "404 Not Found" {
+		return err
+	}
+
+	// The id may be stale, e.g. after Grafana's database was restored, while
+	// the datasource still exists under its uid or name.
+	dataSource, lookupErr := c.findDataSource(d.Get("uid").(string), d.Get("name").(string))
+	if lookupErr != nil {
+		return lookupErr
+	}
+	if dataSource == nil {
+		return err
+	}
+	log.Printf("[WARN] datasource %d no longer exists, deleting datasource %d with the same uid or name instead", id, dataSource.Id)
+	return c.gapi.DeleteDataSource(dataSource.Id)
+}
+
+// ValidateDataSourceAccessMode checks that a value is one of the ways Grafana
+// can access a datasource, warning about direct access, which Grafana
+// deprecated and most datasource types don't support.
+func ValidateDataSourceAccessMode(modeI interface{}, k string) ([]string, []error) {
+	switch mode := modeI.(string); mode {
+	case "proxy":
+		return nil, nil
+	case "direct":
+		return []string{fmt.Sprintf("%s: direct access is deprecated, the browser queries the datasource itself and most datasource types don't support it, use proxy instead", k)}, nil
+	default:
+		return nil, []error{fmt.Errorf("%s: %q is not a valid access mode, expected proxy or direct", k, mode)}
+	}
+}
+
+// proxyOnlyDataSourceTypes are the datasource types that are queried by the
+// Grafana server only and can't be accessed directly by the browser.
+var proxyOnlyDataSourceTypes = map[string]bool{
+	"cloudwatch":                       true,
+	"grafana-azure-monitor-datasource": true,
+	"loki":                             true,
+	"mssql":                            true,
+	"mysql":                            true,
+	"postgres":                         true,
+	"stackdriver":                      true,
+	"tempo":                            true,
+}
+
+// CustomizeDataSourceDiff fails the plan when a datasource type that only
+// supports proxy access is configured with direct access, as its queries
+// would fail, or when another datasource is configured as the default one.
+// The default one is claimed whenever is_default is set, not only when it
+// changes, to catch datasources made the default in separate applies.
+func CustomizeDataSourceDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.NewValueKnown("name") && d.NewValueKnown("is_default") && d.Get("is_default").(bool) {
+		if err := meta.(*client).claimDefaultDataSource(d.Get("name").(string)); err != nil {
+			return err
+		}
+	}
+	if !d.NewValueKnown("type") || !d.NewValueKnown("access_mode") {
+		return nil
+	}
+	dsType := d.Get("type").(string)
+	if d.Get("access_mode").(string) == "direct" && proxyOnlyDataSourceTypes[dsType] {
+		return fmt.Errorf("Error: Grafana can only access %s datasources with access_mode proxy.", dsType)
+	}
+	return nil
+}
+
+// ImportDataSource imports a datasource by its numeric id or by its uid, which
+// is stable across Grafana instances and database restores.
+func ImportDataSource(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(*client)
+	if _, err := strconv.ParseInt(d.Id(), 10, 64); err != nil {
+		uid := d.Id()
+		dataSource := dataSourceResponse{}
+		err := c.request("GET", "/api/datasources/uid/"+url.PathEscape(uid), nil, nil, &dataSource)
+		if err != nil && err.Error() == "404 Not Found" {
+			return nil, fmt.Errorf("Error: A Grafana datasource with the uid '%s' does not exist.", uid)
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(strconv.FormatInt(dataSource.Id, 10))
+	}
+	d.Set("test_on_create", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// findDataSource looks a datasource up by its uid, when given, and then by
+// its name. It returns nil when neither matches a datasource.
+func (c *client) findDataSource(uid, name string) (*dataSourceResponse, error) {
+	var paths []string
+	if uid != "" {
+		paths = append(paths, "/api/datasources/uid/"+url.PathEscape(uid))
+	}
+	paths = append(paths, "/api/datasources/name/"+url.PathEscape(name))
+	for _, path := range paths {
+		dataSource := &dataSourceResponse{}
+		err := c.request("GET", path, nil, nil, dataSource)
+		if err != nil && err.Error() == "404 Not Found" {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return dataSource, nil
+	}
+	return nil, nil
 }
 
 func makeDataSource(d *schema.ResourceData) (*gapi.DataSource, error) {
@@ -236,6 +502,98 @@ func makeDataSource(d *schema.ResourceData) (*gapi.DataSource, error) {
 	}, err
 }
 
+// dataSourceDatabaseKeys are the jsonData keys that the database of some
+// datasource types moved to, along with the Grafana version they moved in.
+// The top-level database is still sent for older versions.
+var dataSourceDatabaseKeys = map[string]struct{ key, since string }{
+	"influxdb":      {"dbName", "8.0.0"},
+	"elasticsearch": {"index", "9.0.0"},
+}
+
+// dataSourceDatabase returns the database of a datasource from wherever the
+// Grafana version that saved it put it.
+func dataSourceDatabase(dataSource *dataSourceResponse) string {
+	if k, ok := dataSourceDatabaseKeys[dataSource.Type]; ok {
+		if database, _ := dataSource.JSONData[k.key].(string); database != "" {
+			return database
+		}
+	}
+	return dataSource.Database
+}
+
+// makeDataSourceBody returns the datasource to send to Grafana with its uid
+// and the custom HTTP headers added as numbered pairs of names in jsonData
+// and values in secureJsonData. The database is also added to jsonData for
+// the types and Grafana versions that expect it there.
+func makeDataSourceBody(d *schema.ResourceData, c *client) (map[string]interface{}, error) {
+	dataSource, err := makeDataSource(d)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(dataSource)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, err
+	}
+	jsonData, _ := body["jsonData"].(map[string]interface{})
+	if jsonData == nil {
+		jsonData = map[string]interface{}{}
+	}
+	secureJSONData, _ := body["secureJsonData"].(map[string]interface{})
+	if secureJSONData == nil {
+		secureJSONData = map[string]interface{}{}
+	}
+
+	headers := d.Get("http_headers").(map[string]interface{})
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		jsonData[fmt.Sprintf("httpHeaderName%d", i+1)] = name
+		secureJSONData[fmt.Sprintf("httpHeaderValue%d", i+1)] = headers[name]
+	}
+
+	if k, ok := dataSourceDatabaseKeys[d.Get("type").(string)]; ok && d.Get("database_name").(string) != "" {
+		instance, err := c.instance()
+		if err != nil {
+			return nil, err
+		}
+		if instance.atLeast(k.since) {
+			jsonData[k.key] = d.Get("database_name").(string)
+		}
+	}
+
+	body["jsonData"] = jsonData
+	body["secureJsonData"] = secureJSONData
+	// Grafana generates a uid when none is given.
+	if uid := d.Get("uid").(string); uid != "" {
+		body["uid"] = uid
+	}
+	return body, nil
+}
+
+// readHTTPHeaders returns the custom HTTP headers of a datasource. Grafana
+// never returns the header values, so those are kept from the state and
+// headers added outside of Terraform show up with an empty value.
+func readHTTPHeaders(jsonData map[string]interface{}, current map[string]interface{}) map[string]interface{} {
+	headers := map[string]interface{}{}
+	for key, name := range jsonData {
+		name, ok := name.(string)
+		if !ok || !httpHeaderNameKey.MatchString(key) {
+			continue
+		}
+		value, _ := current[name].(string)
+		headers[name] = value
+	}
+	return headers
+}
+
 func makeJSONData(d *schema.ResourceData) gapi.JSONData {
 	return gapi.JSONData{
 		AuthType:                d.Get("json_data.0.auth_type").(string),
@@ -1,14 +1,21 @@
 package grafana
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
 	gapi "github.com/nytm/go-grafana-api"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
@@ -36,6 +43,9 @@ func TestAccDataSource_basic(t *testing.T) {
 					resource.TestMatchResourceAttr(
 						"grafana_data_source.test_influxdb", "id", regexp.MustCompile(`\d+`),
 					),
+					resource.TestMatchResourceAttr(
+						"grafana_data_source.test_influxdb", "uid", regexp.MustCompile(`\w+`),
+					),
 				),
 			},
 		},
@@ -78,6 +88,548 @@ func TestAccDataSource_basicCloudwatch(t *testing.T) {
 	})
 }
 
+func TestAccDataSource_uid(t *testing.T) {
+	var dataSource gapi.DataSource
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDataSourceCheckDestroy(&dataSource),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceConfig_uid,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceCheckExists("grafana_data_source.test_uid", &dataSource),
+					resource.TestCheckResourceAttr(
+						"grafana_data_source.test_uid", "uid", "terraform-acc-test-uid",
+					),
+				),
+			},
+			{
+				ResourceName:      "grafana_data_source.test_uid",
+				ImportState:       true,
+				ImportStateId:     "terraform-acc-test-uid",
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccDataSource_httpHeaders(t *testing.T) {
+	var dataSource gapi.DataSource
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccDataSourceCheckDestroy(&dataSource),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceConfig_httpHeaders,
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceCheckExists("grafana_data_source.test_prometheus", &dataSource),
+					resource.TestCheckResourceAttr(
+						"grafana_data_source.test_prometheus", "http_headers.%", "2",
+					),
+					resource.TestCheckResourceAttr(
+						"grafana_data_source.test_prometheus", "http_headers.X-Scope-OrgID", "tenant-a",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceHTTPHeaders(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			json.NewDecoder(r.Body).Decode(&sent)
+			fmt.Fprint(w, `{"id": 7}`)
+		case "GET":
+			fmt.Fprint(w, `{
+				"id": 7, "name": "mimir", "type": "prometheus",
+				"jsonData": {"httpHeaderName1": "Authorization", "httpHeaderName2": "X-Scope-OrgID", "httpMethod": "POST"},
+				"secureJsonFields": {"httpHeaderValue1": true, "httpHeaderValue2": true}
+			}`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := map[string]interface{}{
+		"X-Scope-OrgID": "tenant-a",
+		"Authorization": "Bearer secret",
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{
+		"type":         "prometheus",
+		"name":         "mimir",
+		"http_headers": headers,
+	})
+	if err := CreateDataSource(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	jsonData := sent["jsonData"].(map[string]interface{})
+	secureJSONData := sent["secureJsonData"].(map[string]interface{})
+	if jsonData["httpHeaderName1"] != "Authorization" || secureJSONData["httpHeaderValue1"] != "Bearer secret" {
+		t.Errorf("expected Authorization to be sent as the first header, got %v and %v", jsonData, secureJSONData)
+	}
+	if jsonData["httpHeaderName2"] != "X-Scope-OrgID" || secureJSONData["httpHeaderValue2"] != "tenant-a" {
+		t.Errorf("expected X-Scope-OrgID to be sent as the second header, got %v and %v", jsonData, secureJSONData)
+	}
+
+	// The values aren't returned by Grafana and must be kept from the state.
+	if got := d.Get("http_headers").(map[string]interface{}); !reflect.DeepEqual(got, headers) {
+		t.Errorf("expected http_headers %v, got %v", headers, got)
+	}
+}
+
+func TestDataSourceDatabase_versions(t *testing.T) {
+	cases := []struct {
+		version string
+		stored  string
+		inJSON  bool
+	}{
+		// Older versions keep the database at the top level.
+		{"7.5.0", `"database": "metrics", "jsonData": {}`, false},
+		// Newer versions only return it in jsonData.
+		{"8.3.0", `"database": "", "jsonData": {"dbName": "metrics"}`, true},
+	}
+	for _, tc := range cases {
+		var sent map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/health":
+				fmt.Fprintf(w, `{"version": %q}`, tc.version)
+			case r.URL.Path == "/api/frontend/settings":
+				fmt.Fprintf(w, `{"buildInfo": {"version": %q, "edition": "Open Source"}}`, tc.version)
+			case r.Method == "POST":
+				json.NewDecoder(r.Body).Decode(&sent)
+				fmt.Fprint(w, `{"id": 4}`)
+			case r.Method == "GET":
+				fmt.Fprintf(w, `{"id": 4, "name": "influx", "type": "influxdb", %s}`, tc.stored)
+			}
+		}))
+
+		c, err := newClient("token", server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{
+			"type":          "influxdb",
+			"name":          "influx",
+			"database_name": "metrics",
+		})
+		if err := CreateDataSource(d, c); err != nil {
+			t.Fatal(err)
+		}
+		server.Close()
+
+		if sent["database"] != "metrics" {
+			t.Errorf("%s: expected the database to be sent at the top level, got %v", tc.version, sent["database"])
+		}
+		dbName, ok := sent["jsonData"].(map[string]interface{})["dbName"]
+		if ok != tc.inJSON || (ok && dbName != "metrics") {
+			t.Errorf("%s: expected dbName in jsonData to be %v, got %v", tc.version, tc.inJSON, sent["jsonData"])
+		}
+		if got := d.Get("database_name").(string); got != "metrics" {
+			t.Errorf("%s: expected database_name metrics, got %q", tc.version, got)
+		}
+	}
+}
+
+func TestUpdateDataSource_secureJSONDataKeepers(t *testing.T) {
+	var sent map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/datasources/5" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &terraform.InstanceState{ID: "5", Attributes: map[string]string{
+		"type":                                 "cloudwatch",
+		"name":                                 "cloudwatch",
+		"access_mode":                          "proxy",
+		"secure_json_data.#":                   "1",
+		"secure_json_data.0.access_key":        "AKIA2",
+		"secure_json_data.0.secret_key":        "rotated",
+		"secure_json_data_keepers.%":           "1",
+		"secure_json_data_keepers.key_version": "1",
+	}}
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"secure_json_data_keepers.key_version": {Old: "1", New: "2"},
+	}}
+	d, err := schema.InternalMap(ResourceDataSource().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.HasChange("secure_json_data_keepers") {
+		t.Fatal("expected changing a keeper to change the datasource")
+	}
+	if err := UpdateDataSource(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	secureJSONData := sent["secureJsonData"].(map[string]interface{})
+	if secureJSONData["accessKey"] != "AKIA2" || secureJSONData["secretKey"] != "rotated" {
+		t.Errorf("expected the secrets to be sent again, got %v", secureJSONData)
+	}
+}
+
+func TestReadDataSource_serverDefaults(t *testing.T) {
+	// Grafana decorates datasources with defaults that were never configured,
+	// and the datasource was changed outside of Terraform since the last run.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"id": 3, "uid": "prom", "name": "prometheus", "type": "prometheus", "access": "direct",
+			"url": "http://prometheus.internal/", "basicAuth": true, "basicAuthUser": "scraper",
+			"jsonData": {"authType": "keys", "httpMethod": "POST", "tlsSkipVerify": false, "timeInterval": "15s", "manageAlerts": true}
+		}`)
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{
+		"type":      "prometheus",
+		"name":      "prometheus",
+		"url":       "http://prometheus.invalid/",
+		"json_data": []interface{}{map[string]interface{}{"auth_type": "keys"}},
+	})
+	d.SetId("3")
+	if err := ReadDataSource(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changes made in Grafana show up in the state, so they're planned back.
+	for key, want := range map[string]interface{}{
+		"url":                 "http://prometheus.internal/",
+		"access_mode":         "direct",
+		"basic_auth_enabled":  true,
+		"basic_auth_username": "scraper",
+	} {
+		if got := d.Get(key); got != want {
+			t.Errorf("expected %s to be %v, got %v", key, want, got)
+		}
+	}
+	// The jsonData defaults Grafana added don't show up as changes.
+	expected := []interface{}{map[string]interface{}{
+		"auth_type":                 "keys",
+		"default_region":            "",
+		"custom_metrics_namespaces": "",
+		"assume_role_arn":           "",
+	}}
+	if jsonData := d.Get("json_data"); !reflect.DeepEqual(jsonData, expected) {
+		t.Errorf("expected json_data %v, got %v", expected, jsonData)
+	}
+	if n := len(d.Get("http_headers").(map[string]interface{})); n != 0 {
+		t.Errorf("expected no http_headers, got %v", d.Get("http_headers"))
+	}
+}
+
+func TestDeleteDataSource_staleId(t *testing.T) {
+	deleted := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "DELETE" && r.URL.Path == "/api/datasources/3":
+			http.NotFound(w, r)
+		case r.Method == "GET" && r.URL.Path == "/api/datasources/uid/prom":
+			http.NotFound(w, r)
+		case r.Method == "GET" && r.URL.Path == "/api/datasources/name/prometheus":
+			fmt.Fprint(w, `{"id": 8, "uid": "other", "name": "prometheus", "type": "prometheus"}`)
+		case r.Method == "DELETE" && r.URL.Path == "/api/datasources/8":
+			deleted = append(deleted, "8")
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{
+		"type": "prometheus",
+		"name": "prometheus",
+		"uid":  "prom",
+	})
+	d.SetId("3")
+	if err := DeleteDataSource(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(deleted, []string{"8"}) {
+		t.Fatalf("expected datasource 8 to be deleted by name, got %v", deleted)
+	}
+}
+
+func TestValidateDataSourceAccessMode(t *testing.T) {
+	cases := []struct {
+		mode            string
+		warned, invalid bool
+	}{
+		{"proxy", false, false},
+		{"direct", true, false},
+		{"server", false, true},
+		{"", false, true},
+	}
+	for _, c := range cases {
+		ws, errs := ValidateDataSourceAccessMode(c.mode, "access_mode")
+		if (len(ws) > 0) != c.warned {
+			t.Errorf("%q: expected a warning to be %v, got %v", c.mode, c.warned, ws)
+		}
+		if (len(errs) > 0) != c.invalid {
+			t.Errorf("%q: expected an error to be %v, got %v", c.mode, c.invalid, errs)
+		}
+	}
+}
+
+func TestAccDataSource_directAccessProxyOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceConfig_directAccessProxyOnly,
+				ExpectError: regexp.MustCompile("can only access mysql datasources with access_mode proxy"),
+			},
+		},
+	})
+}
+
+func TestCustomizeDataSourceDiff_twoDefaults(t *testing.T) {
+	c, err := newClient("token", "http://grafana.invalid/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := func(state *terraform.InstanceState, name string) error {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"type":       "prometheus",
+			"name":       name,
+			"is_default": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ResourceDataSource().Diff(state, terraform.NewResourceConfig(raw), c)
+		return err
+	}
+
+	// Planning the same datasource as the default again succeeds.
+	for i := 0; i < 2; i++ {
+		if err := diff(nil, "prometheus"); err != nil {
+			t.Fatalf("expected planning prometheus as the default to succeed, got %s", err)
+		}
+	}
+
+	// Another datasource that was made the default in an earlier apply is
+	// caught even though its is_default doesn't change.
+	state := &terraform.InstanceState{ID: "2", Attributes: map[string]string{
+		"type":        "prometheus",
+		"name":        "loki",
+		"access_mode": "proxy",
+		"is_default":  "true",
+	}}
+	err = diff(state, "loki")
+	if err == nil || !strings.Contains(err.Error(), "'prometheus' and 'loki' are configured with is_default") {
+		t.Fatalf("expected a conflicting default error, got %v", err)
+	}
+}
+
+func TestReadDataSource_defaultTaken(t *testing.T) {
+	listed := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/datasources/2":
+			fmt.Fprint(w, `{"id": 2, "name": "loki", "type": "loki", "access": "proxy", "isDefault": false}`)
+		case "/api/datasources":
+			listed = true
+			fmt.Fprint(w, `[{"id": 1, "name": "prometheus", "isDefault": true}, {"id": 2, "name": "loki", "isDefault": false}]`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{
+		"type":       "loki",
+		"name":       "loki",
+		"is_default": true,
+	})
+	d.SetId("2")
+	if err := ReadDataSource(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if d.Get("is_default").(bool) {
+		t.Error("expected is_default to be read back as false")
+	}
+	if !listed {
+		t.Error("expected the datasources to be listed to find the new default one")
+	}
+}
+
+func TestReadDataSource_defaultTakenListFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/datasources/2":
+			fmt.Fprint(w, `{"id": 2, "name": "loki", "type": "loki", "access": "proxy", "isDefault": false}`)
+		default:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{
+		"type":       "loki",
+		"name":       "loki",
+		"is_default": true,
+	})
+	d.SetId("2")
+	// Finding the new default one is only for the warning.
+	if err := ReadDataSource(d, c); err != nil {
+		t.Fatalf("expected the refresh to succeed, got %s", err)
+	}
+	if d.Get("is_default").(bool) {
+		t.Error("expected is_default to be read back as false")
+	}
+}
+
+func TestDataSource_readOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `{"id": 6, "uid": "prov", "name": "provisioned", "type": "prometheus", "access": "proxy", "readOnly": true}`)
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{
+		"type": "prometheus",
+		"name": "provisioned",
+	})
+	d.SetId("6")
+	if err := ReadDataSource(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Get("read_only").(bool) {
+		t.Fatal("expected the datasource to be read back as read-only")
+	}
+
+	// Grafana refuses to change provisioned datasources.
+	if err := UpdateDataSource(d, c); err == nil || !strings.Contains(err.Error(), "Terraform can't update it") {
+		t.Errorf("expected a read-only error on update, got %v", err)
+	}
+	if err := DeleteDataSource(d, c); err == nil || !strings.Contains(err.Error(), "Terraform can't delete it") {
+		t.Errorf("expected a read-only error on delete, got %v", err)
+	}
+}
+
+func TestImportDataSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/datasources/uid/prom":
+			fmt.Fprint(w, `{"id": 12, "uid": "prom", "name": "prometheus", "type": "prometheus"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"prom": "12",
+		"7":    "7",
+	}
+	for importId, expected := range cases {
+		d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{})
+		d.SetId(importId)
+		if _, err := ImportDataSource(d, c); err != nil {
+			t.Fatal(err)
+		}
+		if d.Id() != expected {
+			t.Errorf("expected importing %s to resolve to id %s, got %s", importId, expected, d.Id())
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceDataSource().Schema, map[string]interface{}{})
+	d.SetId("missing")
+	if _, err := ImportDataSource(d, c); err == nil || !strings.Contains(err.Error(), "uid 'missing' does not exist") {
+		t.Errorf("expected a missing uid error, got %v", err)
+	}
+}
+
+func TestCheckDataSourceHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/datasources/1/health":
+			fmt.Fprint(w, `{"status": "OK", "message": "Data source is working"}`)
+		case "/api/datasources/2/health":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status": "ERROR", "message": "dial tcp: lookup prometheus.invalid: no such host"}`)
+		case "/api/datasources/3/health":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		id  int64
+		err string
+	}{
+		{1, ""},
+		{2, "can't connect to datasource 2: dial tcp: lookup prometheus.invalid: no such host"},
+		{3, "can't connect to datasource 3: 500 Internal Server Error"},
+		// Datasources without a health check are skipped.
+		{4, ""},
+	}
+	for _, tc := range cases {
+		err := c.checkDataSourceHealth(tc.id)
+		if tc.err == "" && err != nil {
+			t.Errorf("%d: unexpected error %s", tc.id, err)
+		}
+		if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%d: expected error containing %q, got %v", tc.id, tc.err, err)
+		}
+	}
+}
+
 func testAccDataSourceCheckExists(rn string, dataSource *gapi.DataSource) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -94,7 +646,7 @@ func testAccDataSourceCheckExists(rn string, dataSource *gapi.DataSource) resour
 			return fmt.Errorf("resource id is malformed")
 		}
 
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		gotDataSource, err := client.DataSource(id)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
@@ -108,7 +660,7 @@ func testAccDataSourceCheckExists(rn string, dataSource *gapi.DataSource) resour
 
 func testAccDataSourceCheckDestroy(dataSource *gapi.DataSource) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		_, err := client.DataSource(dataSource.Id)
 		if err == nil {
 			return fmt.Errorf("data source still exists")
@@ -146,3 +698,32 @@ resource "grafana_data_source" "test_cloudwatch" {
   }
 }
 `
+const testAccDataSourceConfig_httpHeaders = `
+resource "grafana_data_source" "test_prometheus" {
+  type = "prometheus"
+  name = "terraform-acc-test-prometheus"
+  url  = "http://terraform-acc-test.invalid/"
+
+  http_headers = {
+    "X-Scope-OrgID" = "tenant-a"
+    "Authorization" = "Bearer secret"
+  }
+}
+`
+const testAccDataSourceConfig_uid = `
+resource "grafana_data_source" "test_uid" {
+  type = "prometheus"
+  name = "terraform-acc-test-uid"
+  uid  = "terraform-acc-test-uid"
+  url  = "http://terraform-acc-test.invalid/"
+}
+`
+
+const testAccDataSourceConfig_directAccessProxyOnly = `
+resource "grafana_data_source" "test_direct" {
+  type        = "mysql"
+  name        = "terraform-acc-test-direct"
+  url         = "mysql.invalid:3306"
+  access_mode = "direct"
+}
+`
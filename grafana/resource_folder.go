@@ -7,8 +7,6 @@ import (
 	"strconv"
 
 	"github.com/hashicorp/terraform/helper/schema"
-
-	gapi "github.com/nytm/go-grafana-api"
 )
 
 func ResourceFolder() *schema.Resource {
@@ -33,7 +31,7 @@ func ResourceFolder() *schema.Resource {
 }
 
 func CreateFolder(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 
 	model := d.Get("title").(string)
 
@@ -51,7 +49,7 @@ func CreateFolder(d *schema.ResourceData, meta interface{}) error {
 }
 
 func ReadFolder(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 
 	id, err := strconv.ParseInt(d.Id(), 10, 64)
 	if err != nil {
@@ -76,7 +74,7 @@ func ReadFolder(d *schema.ResourceData, meta interface{}) error {
 }
 
 func DeleteFolder(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 
 	return client.DeleteFolder(d.Get("uid").(string))
 }
@@ -47,7 +47,7 @@ func testAccFolderCheckExists(rn string, folder *gapi.Folder) resource.TestCheck
 			return fmt.Errorf("resource id not set")
 		}
 
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		id, err := strconv.ParseInt(rs.Primary.ID, 10, 64)
 		if err != nil {
 			return err
@@ -71,14 +71,14 @@ func testAccFolderDisappear(folder *gapi.Folder) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		// At this point testAccFolderCheckExists should have been called and
 		// folder should have been populated
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		return client.DeleteFolder((*folder).Uid)
 	}
 }
 
 func testAccFolderCheckDestroy(folder *gapi.Folder) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		_, err := client.Folder(folder.Id)
 		if err == nil {
 			return fmt.Errorf("folder still exists")
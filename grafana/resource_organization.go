@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -25,6 +26,9 @@ type UserChange struct {
 
 type ChangeType int8
 
+// orgUsersPageSize is the number of org users requested at a time.
+const orgUsersPageSize = 1000
+
 const (
 	Add ChangeType = iota
 	Update
@@ -41,6 +45,7 @@ func ResourceOrganization() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: ImportOrganization,
 		},
+		CustomizeDiff: CustomizeOrganizationDiff,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -52,11 +57,31 @@ func ResourceOrganization() *schema.Resource {
 				Optional: true,
 				Default:  "admin",
 			},
+			"ignore_users": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
 			"create_users": {
 				Type:     schema.TypeBool,
 				Optional: true,
 				Default:  true,
 			},
+			"allowed_email_domains": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: ValidateEmailDomain,
+				},
+			},
+			"delete_orphaned_users": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"org_id": {
 				Type:     schema.TypeInt,
 				Computed: true,
@@ -65,29 +90,109 @@ func ResourceOrganization() *schema.Resource {
 				Type:     schema.TypeList,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: ValidateEmail,
 				},
 			},
 			"editors": {
 				Type:     schema.TypeList,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: ValidateEmail,
 				},
 			},
 			"viewers": {
 				Type:     schema.TypeList,
 				Optional: true,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: ValidateEmail,
+				},
+			},
+			"preferences": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"theme": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"home_dashboard_uid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"timezone": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"address": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"address1": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"address2": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"city": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"zip_code": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"country": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
 				},
 			},
 		},
 	}
 }
 
+// CustomizeOrganizationDiff fails the plan when a user is both ignored and
+// listed under a role. Ignored users are left out of the state, so they would
+// show up as added on every plan.
+func CustomizeOrganizationDiff(d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range []string{"ignore_users", "admins", "editors", "viewers"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	ignored := make(map[string]bool)
+	for _, u := range d.Get("ignore_users").([]interface{}) {
+		ignored[u.(string)] = true
+	}
+	for _, role := range []string{"admins", "editors", "viewers"} {
+		for _, u := range d.Get(role).([]interface{}) {
+			if email := u.(string); ignored[email] {
+				return fmt.Errorf("Error: User '%s' cannot be both ignored and listed in %s.", email, role)
+			}
+		}
+	}
+	return nil
+}
+
 func CreateOrganization(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 	name := d.Get("name").(string)
 	orgId, err := client.NewOrg(name)
 	if err != nil && err.Error() == "409 Conflict" {
@@ -97,11 +202,17 @@ func CreateOrganization(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 	d.SetId(strconv.FormatInt(orgId, 10))
+	if err := UpdatePreferences(d, meta); err != nil {
+		return err
+	}
+	if err := UpdateAddress(d, meta); err != nil {
+		return err
+	}
 	return UpdateUsers(d, meta)
 }
 
 func ReadOrganization(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
 	resp, err := client.Org(orgId)
 	if err != nil && err.Error() == "404 Not Found" {
@@ -116,30 +227,50 @@ func ReadOrganization(d *schema.ResourceData, meta interface{}) error {
 	if err := ReadUsers(d, meta); err != nil {
 		return err
 	}
-	return nil
+	if err := ReadPreferences(d, meta); err != nil {
+		return err
+	}
+	return ReadAddress(d, meta)
 }
 
 func UpdateOrganization(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
 	if d.HasChange("name") {
 		name := d.Get("name").(string)
 		err := client.UpdateOrg(orgId, name)
+		if err != nil && err.Error() == "409 Conflict" {
+			return errors.New(fmt.Sprintf("Error: A Grafana Organization with the name '%s' already exists.", name))
+		}
 		if err != nil {
 			return err
 		}
 	}
-	return UpdateUsers(d, meta)
+	if d.HasChange("preferences") {
+		if err := UpdatePreferences(d, meta); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("address") {
+		if err := UpdateAddress(d, meta); err != nil {
+			return err
+		}
+	}
+	if err := UpdateUsers(d, meta); err != nil {
+		return err
+	}
+	return ReadOrganization(d, meta)
 }
 
 func DeleteOrganization(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
 	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
-	return client.DeleteOrg(orgId)
+	c.orgUsers.invalidate(orgId)
+	return c.gapi.DeleteOrg(orgId)
 }
 
 func ExistsOrganization(d *schema.ResourceData, meta interface{}) (bool, error) {
-	client := meta.(*gapi.Client)
+	client := meta.(*client).gapi
 	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
 	_, err := client.Org(orgId)
 	if err != nil && err.Error() == "404 Not Found" {
@@ -158,6 +289,7 @@ func ImportOrganization(d *schema.ResourceData, meta interface{}) ([]*schema.Res
 	}
 	d.Set("admin_user", "admin")
 	d.Set("create_users", "true")
+	d.Set("delete_orphaned_users", false)
 	err = ReadOrganization(d, meta)
 	if err != nil {
 		return nil, err
@@ -166,16 +298,16 @@ func ImportOrganization(d *schema.ResourceData, meta interface{}) ([]*schema.Res
 }
 
 func ReadUsers(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
 	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
-	orgUsers, err := client.OrgUsers(orgId)
+	orgUsers, err := c.orgUsers.get(orgId, c.listOrgUsers)
 	if err != nil {
 		return err
 	}
 	roleMap := map[string][]string{"Admin": nil, "Editor": nil, "Viewer": nil}
-	grafAdmin := d.Get("admin_user")
+	ignored := ignoredUsers(d, orgUsers)
 	for _, orgUser := range orgUsers {
-		if orgUser.Login != grafAdmin {
+		if !ignored[orgUser.Email] {
 			roleMap[orgUser.Role] = append(roleMap[orgUser.Role], orgUser.Email)
 		}
 	}
@@ -185,21 +317,45 @@ func ReadUsers(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// ignoredUsers returns the logins and emails of the admin_user and the
+// ignore_users, which are left out of the state and never removed from the
+// organization. The emails of users ignored by their login are looked up
+// among orgUsers.
+func ignoredUsers(d *schema.ResourceData, orgUsers []gapi.OrgUser) map[string]bool {
+	ignored := map[string]bool{d.Get("admin_user").(string): true}
+	for _, u := range d.Get("ignore_users").([]interface{}) {
+		ignored[u.(string)] = true
+	}
+	for _, orgUser := range orgUsers {
+		if ignored[orgUser.Login] {
+			ignored[orgUser.Email] = true
+		}
+	}
+	return ignored
+}
+
 func UpdateUsers(d *schema.ResourceData, meta interface{}) error {
-	stateUsers, configUsers, err := collectUsers(d)
+	c := meta.(*client)
+	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
+	orgUsers, err := c.orgUsers.get(orgId, c.listOrgUsers)
+	if err != nil {
+		return err
+	}
+	// The state may still hold users ignored since it was last read, e.g.
+	// when ignore_users was just added.
+	stateUsers, configUsers, err := collectUsers(d, ignoredUsers(d, orgUsers))
 	if err != nil {
 		return err
 	}
 	changes := changes(stateUsers, configUsers)
-	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
 	changes, err = addIdsToChanges(d, meta, changes)
 	if err != nil {
 		return err
 	}
-	return applyChanges(meta, orgId, changes)
+	return applyChanges(meta, orgId, changes, d.Get("delete_orphaned_users").(bool))
 }
 
-func collectUsers(d *schema.ResourceData) (map[string]OrgUser, map[string]OrgUser, error) {
+func collectUsers(d *schema.ResourceData, ignored map[string]bool) (map[string]OrgUser, map[string]OrgUser, error) {
 	roles := []string{"admins", "editors", "viewers"}
 	stateUsers, configUsers := make(map[string]OrgUser), make(map[string]OrgUser)
 	for _, role := range roles {
@@ -208,6 +364,9 @@ func collectUsers(d *schema.ResourceData) (map[string]OrgUser, map[string]OrgUse
 		state, config := d.GetChange(role)
 		for _, u := range state.([]interface{}) {
 			email := u.(string)
+			if ignored[email] {
+				continue
+			}
 			// Sanity check that a user isn't specified twice within an organization
 			if _, ok := stateUsers[email]; ok {
 				return nil, nil, errors.New(fmt.Sprintf("Error: User '%s' cannot be specified multiple times.", email))
@@ -216,6 +375,9 @@ func collectUsers(d *schema.ResourceData) (map[string]OrgUser, map[string]OrgUse
 		}
 		for _, u := range config.([]interface{}) {
 			email := u.(string)
+			if ignored[email] {
+				continue
+			}
 			// Sanity check that a user isn't specified twice within an organization
 			if _, ok := configUsers[email]; ok {
 				return nil, nil, errors.New(fmt.Sprintf("Error: User '%s' cannot be specified multiple times.", email))
@@ -252,17 +414,21 @@ func changes(stateUsers, configUsers map[string]OrgUser) []UserChange {
 }
 
 func addIdsToChanges(d *schema.ResourceData, meta interface{}, changes []UserChange) ([]UserChange, error) {
-	client := meta.(*gapi.Client)
-	gUserMap := make(map[string]int64)
-	gUsers, err := client.Users()
+	c := meta.(*client)
+	gUserMap, err := c.users.get(c.gapi.Users)
 	if err != nil {
 		return nil, err
 	}
-	for _, u := range gUsers {
-		gUserMap[u.Email] = u.Id
-	}
 	var output []UserChange
 	create := d.Get("create_users").(bool)
+	// Check every user to be created first, so that none are created when
+	// the apply is going to fail.
+	domains := d.Get("allowed_email_domains").([]interface{})
+	for _, change := range changes {
+		if _, ok := gUserMap[change.User.Email]; !ok && create && !emailDomainAllowed(change.User.Email, domains) {
+			return nil, errors.New(fmt.Sprintf("Error adding user %s. User does not exist in Grafana and the domain of their email isn't one of allowed_email_domains.", change.User.Email))
+		}
+	}
 	for _, change := range changes {
 		id, ok := gUserMap[change.User.Email]
 		if !ok && !create {
@@ -280,8 +446,23 @@ func addIdsToChanges(d *schema.ResourceData, meta interface{}, changes []UserCha
 	return output, nil
 }
 
+// emailDomainAllowed reports whether the domain of email is one of domains,
+// ignoring case. Any domain is allowed when there are none.
+func emailDomainAllowed(email string, domains []interface{}) bool {
+	if len(domains) == 0 {
+		return true
+	}
+	domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+	for _, allowed := range domains {
+		if strings.ToLower(allowed.(string)) == domain {
+			return true
+		}
+	}
+	return false
+}
+
 func createUser(meta interface{}, user string) (int64, error) {
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
 	id, n := int64(0), 64
 	bytes := make([]byte, n)
 	_, err := rand.Read(bytes)
@@ -295,16 +476,20 @@ func createUser(meta interface{}, user string) (int64, error) {
 		Email:    user,
 		Password: pass,
 	}
-	id, err = client.CreateUser(u)
+	id, err = c.gapi.CreateUser(u)
 	if err != nil {
 		return id, err
 	}
+	// The new account isn't part of any cached listing of the user directory.
+	c.users.invalidate()
 	return id, err
 }
 
-func applyChanges(meta interface{}, orgId int64, changes []UserChange) error {
+func applyChanges(meta interface{}, orgId int64, changes []UserChange, deleteOrphans bool) error {
 	var err error
-	client := meta.(*gapi.Client)
+	c := meta.(*client)
+	client := c.gapi
+	defer c.orgUsers.invalidate(orgId)
 	for _, change := range changes {
 		u := change.User
 		switch change.Type {
@@ -314,6 +499,9 @@ func applyChanges(meta interface{}, orgId int64, changes []UserChange) error {
 			err = client.UpdateOrgUser(orgId, u.Id, u.Role)
 		case Remove:
 			err = client.RemoveOrgUser(orgId, u.Id)
+			if err == nil && deleteOrphans {
+				err = c.deleteOrphanedUser(u.Id)
+			}
 		}
 		if err != nil && err.Error() != "409 Conflict" {
 			return err
@@ -321,3 +509,174 @@ func applyChanges(meta interface{}, orgId int64, changes []UserChange) error {
 	}
 	return nil
 }
+
+// deleteOrphanedUser deletes the user when they no longer belong to any
+// organization.
+func (c *client) deleteOrphanedUser(userId int64) error {
+	var orgs []struct {
+		OrgId int64 `json:"orgId"`
+	}
+	err := c.request("GET", fmt.Sprintf("/api/users/%d/orgs", userId), nil, nil, &orgs)
+	if err != nil && err.Error() == "404 Not Found" {
+		// Grafana may have deleted the user along with their last membership.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(orgs) > 0 {
+		return nil
+	}
+	if err := c.gapi.DeleteUser(userId); err != nil {
+		return err
+	}
+	c.users.invalidate()
+	return nil
+}
+
+// listOrgUsers pages through the users of an organization. Grafana versions
+// without the paginated search endpoint return every user in one response.
+func (c *client) listOrgUsers(orgId int64) ([]gapi.OrgUser, error) {
+	var users []gapi.OrgUser
+	query := url.Values{"perpage": {strconv.Itoa(orgUsersPageSize)}}
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		result := struct {
+			TotalCount int            `json:"totalCount"`
+			OrgUsers   []gapi.OrgUser `json:"orgUsers"`
+		}{}
+		err := c.request("GET", fmt.Sprintf("/api/orgs/%d/users/search", orgId), query, nil, &result)
+		if err != nil && err.Error() == "404 Not Found" && page == 1 {
+			return c.gapi.OrgUsers(orgId)
+		}
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, result.OrgUsers...)
+		if len(result.OrgUsers) == 0 || len(users) >= result.TotalCount {
+			return users, nil
+		}
+	}
+}
+
+// orgPreferences are the preferences of an organization, which are the
+// defaults of its teams and users.
+type orgPreferences struct {
+	Theme           string `json:"theme"`
+	HomeDashboardId int64  `json:"homeDashboardId"`
+	Timezone        string `json:"timezone"`
+}
+
+// ReadPreferences reads the organization's preferences when they're managed,
+// i.e. when the preferences block is configured.
+func ReadPreferences(d *schema.ResourceData, meta interface{}) error {
+	if len(d.Get("preferences").([]interface{})) == 0 {
+		return nil
+	}
+	c := meta.(*client)
+	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
+	prefs := orgPreferences{}
+	if err := c.orgRequest(orgId, "GET", "/api/org/preferences", nil, nil, &prefs); err != nil {
+		return err
+	}
+	homeDashboardUid := ""
+	if prefs.HomeDashboardId != 0 {
+		var results []struct {
+			Uid string `json:"uid"`
+		}
+		query := url.Values{"dashboardIds": {strconv.FormatInt(prefs.HomeDashboardId, 10)}}
+		if err := c.orgRequest(orgId, "GET", "/api/search", query, nil, &results); err != nil {
+			return err
+		}
+		if len(results) > 0 {
+			homeDashboardUid = results[0].Uid
+		}
+	}
+	return d.Set("preferences", []map[string]interface{}{{
+		"theme":              prefs.Theme,
+		"home_dashboard_uid": homeDashboardUid,
+		"timezone":           prefs.Timezone,
+	}})
+}
+
+// UpdatePreferences applies the configured preferences. Preferences are left
+// alone when the block is removed.
+func UpdatePreferences(d *schema.ResourceData, meta interface{}) error {
+	if len(d.Get("preferences").([]interface{})) == 0 {
+		return nil
+	}
+	c := meta.(*client)
+	orgId, _ := strconv.ParseInt(d.Id(), 10, 64)
+	prefs := orgPreferences{
+		Theme:    d.Get("preferences.0.theme").(string),
+		Timezone: d.Get("preferences.0.timezone").(string),
+	}
+	// Grafana before 9.0 only takes the id of the home dashboard.
+	if uid := d.Get("preferences.0.home_dashboard_uid").(string); uid != "" {
+		dashboard := struct {
+			Dashboard struct {
+				Id int64 `json:"id"`
+			} `json:"dashboard"`
+		}{}
+		err := c.orgRequest(orgId, "GET", "/api/dashboards/uid/"+url.PathEscape(uid), nil, nil, &dashboard)
+		if err != nil && err.Error() == "404 Not Found" {
+			return fmt.Errorf("Error: A Grafana dashboard with the uid '%s' does not exist in the organization.", uid)
+		}
+		if err != nil {
+			return err
+		}
+		prefs.HomeDashboardId = dashboard.Dashboard.Id
+	}
+	return c.orgRequest(orgId, "PUT", "/api/org/preferences", nil, prefs, nil)
+}
+
+// orgAddress is the postal address of an organization.
+type orgAddress struct {
+	Address1 string `json:"address1"`
+	Address2 string `json:"address2"`
+	City     string `json:"city"`
+	ZipCode  string `json:"zipCode"`
+	State    string `json:"state"`
+	Country  string `json:"country"`
+}
+
+// ReadAddress reads the organization's address when it's managed, i.e. when
+// the address block is configured.
+func ReadAddress(d *schema.ResourceData, meta interface{}) error {
+	if len(d.Get("address").([]interface{})) == 0 {
+		return nil
+	}
+	c := meta.(*client)
+	org := struct {
+		Address orgAddress `json:"address"`
+	}{}
+	if err := c.request("GET", "/api/orgs/"+d.Id(), nil, nil, &org); err != nil {
+		return err
+	}
+	return d.Set("address", []map[string]interface{}{{
+		"address1": org.Address.Address1,
+		"address2": org.Address.Address2,
+		"city":     org.Address.City,
+		"zip_code": org.Address.ZipCode,
+		"state":    org.Address.State,
+		"country":  org.Address.Country,
+	}})
+}
+
+// UpdateAddress applies the configured address. The address is left alone
+// when the block is removed.
+func UpdateAddress(d *schema.ResourceData, meta interface{}) error {
+	if len(d.Get("address").([]interface{})) == 0 {
+		return nil
+	}
+	c := meta.(*client)
+	address := orgAddress{
+		Address1: d.Get("address.0.address1").(string),
+		Address2: d.Get("address.0.address2").(string),
+		City:     d.Get("address.0.city").(string),
+		ZipCode:  d.Get("address.0.zip_code").(string),
+		State:    d.Get("address.0.state").(string),
+		Country:  d.Get("address.0.country").(string),
+	}
+	return c.request("PUT", "/api/orgs/"+d.Id()+"/address", nil, address, nil)
+}
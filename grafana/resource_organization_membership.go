@@ -0,0 +1,159 @@
+package grafana
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func ResourceOrganizationMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: CreateOrganizationMembership,
+		Read:   ReadOrganizationMembership,
+		Update: UpdateOrganizationMembership,
+		Delete: DeleteOrganizationMembership,
+		Importer: &schema.ResourceImporter{
+			State: ImportOrganizationMembership,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"org_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"email": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: ValidateEmail,
+			},
+			"role": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: ValidateOrgRole,
+			},
+			"create_user": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"user_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func CreateOrganizationMembership(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	orgId := int64(d.Get("org_id").(int))
+	email := d.Get("email").(string)
+	role := d.Get("role").(string)
+
+	ids, err := c.users.get(c.gapi.Users)
+	if err != nil {
+		return err
+	}
+	id, ok := ids[email]
+	if !ok && !d.Get("create_user").(bool) {
+		return errors.New(fmt.Sprintf("Error adding user %s. User does not exist in Grafana.", email))
+	}
+	if !ok {
+		if id, err = createUser(meta, email); err != nil {
+			return err
+		}
+	}
+
+	defer c.orgUsers.invalidate(orgId)
+	err = c.gapi.AddOrgUser(orgId, email, role)
+	if err != nil && err.Error() == "409 Conflict" {
+		// The user is already a member, take over their membership.
+		err = c.gapi.UpdateOrgUser(orgId, id, role)
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s", orgId, email))
+	return ReadOrganizationMembership(d, meta)
+}
+
+func ReadOrganizationMembership(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	orgId, email, err := parseOrganizationMembershipId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	orgUsers, err := c.orgUsers.get(orgId, c.listOrgUsers)
+	if err != nil && err.Error() == "404 Not Found" {
+		log.Printf("[WARN] removing membership %s from state because organization %d no longer exists in grafana", d.Id(), orgId)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, u := range orgUsers {
+		if u.Email == email {
+			d.Set("org_id", orgId)
+			d.Set("email", u.Email)
+			d.Set("role", u.Role)
+			d.Set("user_id", u.UserId)
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] removing membership %s from state because the user is no longer a member of the organization", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func UpdateOrganizationMembership(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	orgId := int64(d.Get("org_id").(int))
+	defer c.orgUsers.invalidate(orgId)
+	err := c.gapi.UpdateOrgUser(orgId, int64(d.Get("user_id").(int)), d.Get("role").(string))
+	if err != nil {
+		return err
+	}
+	return ReadOrganizationMembership(d, meta)
+}
+
+func DeleteOrganizationMembership(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*client)
+	orgId := int64(d.Get("org_id").(int))
+	defer c.orgUsers.invalidate(orgId)
+	err := c.gapi.RemoveOrgUser(orgId, int64(d.Get("user_id").(int)))
+	if err != nil && err.Error() != "404 Not Found" {
+		return err
+	}
+	return nil
+}
+
+// ImportOrganizationMembership sets create_user to its default, as it's only
+// used when adding the user and can't be read from Grafana.
+func ImportOrganizationMembership(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("create_user", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+// parseOrganizationMembershipId splits the "{org_id}:{email}" id of a
+// membership.
+func parseOrganizationMembershipId(id string) (int64, string, error) {
+	parts := strings.SplitN(id, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, "", fmt.Errorf("Invalid id: %#v, expected {org_id}:{email}", id)
+	}
+	orgId, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("Invalid id: %#v, expected {org_id}:{email}", id)
+	}
+	return orgId, parts[1], nil
+}
@@ -0,0 +1,178 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gapi "github.com/nytm/go-grafana-api"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccOrganizationMembership_basic(t *testing.T) {
+	var org gapi.Org
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccOrganizationCheckDestroy(&org),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrganizationMembershipConfig("Viewer"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccOrganizationCheckExists("grafana_organization.test", &org),
+					testAccOrganizationMembershipCheckRole("grafana_organization_membership.test", "Viewer"),
+					resource.TestCheckResourceAttr(
+						"grafana_organization_membership.test", "email", "jane.doe@example.com",
+					),
+				),
+			},
+			{
+				Config: testAccOrganizationMembershipConfig("Editor"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccOrganizationMembershipCheckRole("grafana_organization_membership.test", "Editor"),
+				),
+			},
+			{
+				ResourceName:      "grafana_organization_membership.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestOrganizationMembership(t *testing.T) {
+	members := map[string]gapi.OrgUser{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/users":
+			fmt.Fprint(w, `[{"id": 5, "email": "jane.doe@example.com", "login": "jane"}]`)
+		case r.Method == "POST" && r.URL.Path == "/api/orgs/2/users":
+			var body struct {
+				LoginOrEmail string `json:"loginOrEmail"`
+				Role         string `json:"role"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			members[body.LoginOrEmail] = gapi.OrgUser{OrgId: 2, UserId: 5, Email: body.LoginOrEmail, Role: body.Role}
+			fmt.Fprint(w, `{}`)
+		case r.Method == "GET" && r.URL.Path == "/api/orgs/2/users/search":
+			users := []gapi.OrgUser{}
+			for _, u := range members {
+				users = append(users, u)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"totalCount": len(users), "orgUsers": users})
+		case r.Method == "DELETE" && r.URL.Path == "/api/orgs/2/users/5":
+			delete(members, "jane.doe@example.com")
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceOrganizationMembership().Schema, map[string]interface{}{
+		"org_id": 2,
+		"email":  "jane.doe@example.com",
+		"role":   "Editor",
+	})
+	if err := CreateOrganizationMembership(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "2:jane.doe@example.com" {
+		t.Errorf("expected id 2:jane.doe@example.com, got %q", d.Id())
+	}
+	if got := d.Get("user_id").(int); got != 5 {
+		t.Errorf("expected user_id 5, got %d", got)
+	}
+	if got := d.Get("role").(string); got != "Editor" {
+		t.Errorf("expected role Editor, got %q", got)
+	}
+
+	if err := DeleteOrganizationMembership(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := ReadOrganizationMembership(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the removed membership to be dropped from state, got id %q", d.Id())
+	}
+}
+
+func TestImportOrganizationMembership(t *testing.T) {
+	d := ResourceOrganizationMembership().Data(nil)
+	d.SetId("2:jane.doe@example.com")
+	imported, err := ImportOrganizationMembership(d, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Left unset, create_user would show up as a change to true.
+	if v, ok := imported[0].GetOk("create_user"); !ok || !v.(bool) {
+		t.Errorf("expected create_user to be set to true, got %v", v)
+	}
+}
+
+func TestParseOrganizationMembershipId(t *testing.T) {
+	orgId, email, err := parseOrganizationMembershipId("3:john:doe@example.com")
+	if err != nil || orgId != 3 || email != "john:doe@example.com" {
+		t.Errorf("expected 3 and john:doe@example.com, got %d, %q and %v", orgId, email, err)
+	}
+	for _, id := range []string{"", "3", "3:", "org:john.doe@example.com"} {
+		if _, _, err := parseOrganizationMembershipId(id); err == nil {
+			t.Errorf("expected %q to be invalid", id)
+		}
+	}
+}
+
+func testAccOrganizationMembershipCheckRole(rn, role string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+		orgId, email, err := parseOrganizationMembershipId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		client := testAccProvider.Meta().(*client).gapi
+		users, err := client.OrgUsers(orgId)
+		if err != nil {
+			return fmt.Errorf("error getting organization users: %s", err)
+		}
+		for _, u := range users {
+			if u.Email == email {
+				if u.Role != role {
+					return fmt.Errorf("expected role %s, got %s", role, u.Role)
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("%s is not a member of organization %d", email, orgId)
+	}
+}
+
+func testAccOrganizationMembershipConfig(role string) string {
+	return fmt.Sprintf(`
+resource "grafana_organization" "test" {
+    name = "terraform-acc-test-membership"
+}
+
+resource "grafana_organization_membership" "test" {
+    org_id = "${grafana_organization.test.id}"
+    email  = "jane.doe@example.com"
+    role   = "%s"
+}
+`, role)
+}
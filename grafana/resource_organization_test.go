@@ -1,12 +1,19 @@
 package grafana
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	gapi "github.com/nytm/go-grafana-api"
 )
@@ -44,6 +51,58 @@ func TestAccOrganization_basic(t *testing.T) {
 	})
 }
 
+func TestAccOrganization_rename(t *testing.T) {
+	var org, renamed gapi.Org
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccOrganizationCheckDestroy(&org),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrganizationConfig_basic,
+				Check:  testAccOrganizationCheckExists("grafana_organization.test", &org),
+			},
+			// Renaming updates the organization in place.
+			{
+				Config: testAccOrganizationConfig_updateName,
+				Check: resource.ComposeTestCheckFunc(
+					testAccOrganizationCheckExists("grafana_organization.test", &renamed),
+					func(s *terraform.State) error {
+						if renamed.Id != org.Id {
+							return fmt.Errorf("expected organization %d to be renamed, got organization %d", org.Id, renamed.Id)
+						}
+						return nil
+					},
+				),
+			},
+			// A rename outside of Terraform is detected and reverted.
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*client).gapi
+					if err := client.UpdateOrg(org.Id, "terraform-acc-test-renamed"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccOrganizationConfig_updateName,
+				Check: resource.ComposeTestCheckFunc(
+					testAccOrganizationCheckExists("grafana_organization.test", &renamed),
+					func(s *terraform.State) error {
+						if renamed.Name != "terraform-acc-test-update" {
+							return fmt.Errorf("expected the organization to be renamed back, got %q", renamed.Name)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:      testAccOrganizationConfig_nameConflict,
+				ExpectError: regexp.MustCompile("Organization with the name 'terraform-acc-test-other' already exists"),
+			},
+		},
+	})
+}
+
 func TestAccOrganization_users(t *testing.T) {
 	var org gapi.Org
 
@@ -179,6 +238,476 @@ func TestAccOrganization_defaultAdmin(t *testing.T) {
 	})
 }
 
+func TestAccOrganization_preferences(t *testing.T) {
+	var org gapi.Org
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccOrganizationCheckDestroy(&org),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrganizationConfig_preferences("dark"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccOrganizationCheckExists("grafana_organization.test", &org),
+					resource.TestCheckResourceAttr(
+						"grafana_organization.test", "preferences.0.theme", "dark",
+					),
+					resource.TestCheckResourceAttr(
+						"grafana_organization.test", "preferences.0.timezone", "utc",
+					),
+				),
+			},
+			{
+				Config: testAccOrganizationConfig_preferences("light"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"grafana_organization.test", "preferences.0.theme", "light",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestAccOrganization_address(t *testing.T) {
+	var org gapi.Org
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccOrganizationCheckDestroy(&org),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrganizationConfig_address("Berlin"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccOrganizationCheckExists("grafana_organization.test", &org),
+					resource.TestCheckResourceAttr(
+						"grafana_organization.test", "address.0.city", "Berlin",
+					),
+					resource.TestCheckResourceAttr(
+						"grafana_organization.test", "address.0.country", "Germany",
+					),
+				),
+			},
+			{
+				Config: testAccOrganizationConfig_address("Hamburg"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"grafana_organization.test", "address.0.city", "Hamburg",
+					),
+				),
+			},
+		},
+	})
+}
+
+func TestUpdateOrganization_nameConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/api/orgs/5" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	c, err := newClient("admin:admin", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &terraform.InstanceState{ID: "5", Attributes: map[string]string{"name": "Ops"}}
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"name": {Old: "Ops", New: "Main Org."},
+	}}
+	d, err := schema.InternalMap(ResourceOrganization().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = UpdateOrganization(d, c)
+	if err == nil || err.Error() != "Error: A Grafana Organization with the name 'Main Org.' already exists." {
+		t.Fatalf("expected a name conflict error, got %v", err)
+	}
+}
+
+func TestUpdateUsers_deleteOrphanedUsers(t *testing.T) {
+	cases := []struct {
+		deleteOrphans bool
+		userOrgs      string
+		deleted       bool
+	}{
+		{false, `[]`, false},
+		{true, `[{"orgId": 1}]`, false},
+		{true, `[]`, true},
+	}
+	for _, tc := range cases {
+		removed, deleted := false, false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "GET" && r.URL.Path == "/api/orgs/4/users/search":
+				fmt.Fprint(w, `{"totalCount": 1, "orgUsers": [{"userId": 9, "login": "gone", "email": "gone@example.com", "role": "Viewer"}]}`)
+			case r.Method == "GET" && r.URL.Path == "/api/users":
+				fmt.Fprint(w, `[{"id": 9, "email": "gone@example.com", "login": "gone"}]`)
+			case r.Method == "DELETE" && r.URL.Path == "/api/orgs/4/users/9":
+				removed = true
+				fmt.Fprint(w, `{}`)
+			case r.Method == "GET" && r.URL.Path == "/api/users/9/orgs":
+				fmt.Fprint(w, tc.userOrgs)
+			case r.Method == "DELETE" && r.URL.Path == "/api/admin/users/9":
+				deleted = true
+				fmt.Fprint(w, `{}`)
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				http.NotFound(w, r)
+			}
+		}))
+
+		c, err := newClient("admin:admin", server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := &terraform.InstanceState{ID: "4", Attributes: map[string]string{
+			"name":                  "Ops",
+			"create_users":          "false",
+			"delete_orphaned_users": strconv.FormatBool(tc.deleteOrphans),
+			"viewers.#":             "1",
+			"viewers.0":             "gone@example.com",
+		}}
+		diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+			"viewers.#": {Old: "1", New: "0"},
+			"viewers.0": {Old: "gone@example.com", NewRemoved: true},
+		}}
+		d, err := schema.InternalMap(ResourceOrganization().Schema).Data(state, diff)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := UpdateUsers(d, c); err != nil {
+			t.Fatal(err)
+		}
+		server.Close()
+
+		if !removed {
+			t.Errorf("delete_orphaned_users=%v, orgs %s: expected the user to be removed from the organization", tc.deleteOrphans, tc.userOrgs)
+		}
+		if deleted != tc.deleted {
+			t.Errorf("delete_orphaned_users=%v, orgs %s: expected the user to be deleted to be %v", tc.deleteOrphans, tc.userOrgs, tc.deleted)
+		}
+	}
+}
+
+func TestUpdateUsers_allowedEmailDomains(t *testing.T) {
+	cases := []struct {
+		viewers []string
+		created []string
+		err     string
+	}{
+		{[]string{"jane@Example.com", "john@example.com"}, []string{"jane@Example.com"}, ""},
+		// Nobody is created when one of the users can't be.
+		{[]string{"jane@example.com", "typo@exmaple.com"}, nil, "Error adding user typo@exmaple.com."},
+	}
+	for _, tc := range cases {
+		var created []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "GET" && r.URL.Path == "/api/orgs/4/users/search":
+				fmt.Fprint(w, `{"totalCount": 0, "orgUsers": []}`)
+			case r.Method == "GET" && r.URL.Path == "/api/users":
+				fmt.Fprint(w, `[{"id": 2, "login": "john", "email": "john@example.com"}]`)
+			case r.Method == "POST" && r.URL.Path == "/api/admin/users":
+				var u gapi.User
+				json.NewDecoder(r.Body).Decode(&u)
+				created = append(created, u.Email)
+				fmt.Fprint(w, `{"id": 3}`)
+			case r.Method == "POST" && r.URL.Path == "/api/orgs/4/users":
+				fmt.Fprint(w, `{}`)
+			default:
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				http.NotFound(w, r)
+			}
+		}))
+
+		c, err := newClient("admin:admin", server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		viewers := make([]interface{}, len(tc.viewers))
+		for i, v := range tc.viewers {
+			viewers[i] = v
+		}
+		d := schema.TestResourceDataRaw(t, ResourceOrganization().Schema, map[string]interface{}{
+			"name":                  "Ops",
+			"allowed_email_domains": []interface{}{"example.com"},
+			"viewers":               viewers,
+		})
+		d.SetId("4")
+		err = UpdateUsers(d, c)
+		server.Close()
+
+		if tc.err == "" && err != nil {
+			t.Errorf("%v: unexpected error %s", tc.viewers, err)
+		}
+		if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%v: expected error containing %q, got %v", tc.viewers, tc.err, err)
+		}
+		if !reflect.DeepEqual(created, tc.created) {
+			t.Errorf("%v: expected %v to be created, got %v", tc.viewers, tc.created, created)
+		}
+	}
+}
+
+func TestUpdateAddress(t *testing.T) {
+	var saved orgAddress
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/api/orgs/4/address":
+			json.NewDecoder(r.Body).Decode(&saved)
+			fmt.Fprint(w, `{}`)
+		case r.Method == "GET" && r.URL.Path == "/api/orgs/4":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 4, "name": "Ops", "address": saved})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("admin:admin", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := map[string]interface{}{
+		"address1": "Main Street 1",
+		"city":     "Berlin",
+		"zip_code": "10115",
+		"country":  "Germany",
+	}
+	d := schema.TestResourceDataRaw(t, ResourceOrganization().Schema, map[string]interface{}{
+		"name":    "Ops",
+		"address": []interface{}{address},
+	})
+	d.SetId("4")
+	if err := UpdateAddress(d, c); err != nil {
+		t.Fatal(err)
+	}
+	expected := orgAddress{Address1: "Main Street 1", City: "Berlin", ZipCode: "10115", Country: "Germany"}
+	if saved != expected {
+		t.Errorf("expected address %+v to be saved, got %+v", expected, saved)
+	}
+
+	d.Set("address", []interface{}{map[string]interface{}{"city": "Hamburg"}})
+	if err := ReadAddress(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Get("address.0.city").(string); got != "Berlin" {
+		t.Errorf("expected the city to be read back as Berlin, got %s", got)
+	}
+}
+
+func TestReadUsers_ignoreUsers(t *testing.T) {
+	var removed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/orgs/4/users/search":
+			fmt.Fprint(w, `{"totalCount": 3, "orgUsers": [
+				{"userId": 1, "login": "admin", "email": "admin@localhost", "role": "Admin"},
+				{"userId": 2, "login": "terraform", "email": "terraform@example.com", "role": "Admin"},
+				{"userId": 3, "login": "jane", "email": "jane@example.com", "role": "Viewer"}
+			]}`)
+		case r.Method == "GET" && r.URL.Path == "/api/users":
+			fmt.Fprint(w, `[
+				{"id": 1, "login": "admin", "email": "admin@localhost"},
+				{"id": 2, "login": "terraform", "email": "terraform@example.com"},
+				{"id": 3, "login": "jane", "email": "jane@example.com"}
+			]`)
+		case r.Method == "DELETE":
+			removed = append(removed, r.URL.Path)
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("admin:admin", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := schema.TestResourceDataRaw(t, ResourceOrganization().Schema, map[string]interface{}{
+		"name":         "Ops",
+		"ignore_users": []interface{}{"terraform@example.com"},
+	})
+	d.SetId("4")
+	if err := ReadUsers(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if admins := d.Get("admins").([]interface{}); len(admins) != 0 {
+		t.Errorf("expected the ignored users to be left out, got admins %v", admins)
+	}
+
+	// Only the viewer is removed when the organization is synced with an
+	// empty membership.
+	state := d.State()
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"viewers.#": {Old: "1", New: "0"},
+		"viewers.0": {Old: "jane@example.com", NewRemoved: true},
+	}}
+	d, err = schema.InternalMap(ResourceOrganization().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateUsers(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"/api/orgs/4/users/3"}; !reflect.DeepEqual(removed, expected) {
+		t.Errorf("expected only %v to be removed, got %v", expected, removed)
+	}
+}
+
+func TestUpdateUsers_newlyIgnored(t *testing.T) {
+	var removed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/orgs/4/users/search":
+			fmt.Fprint(w, `{"totalCount": 2, "orgUsers": [
+				{"userId": 1, "login": "admin", "email": "admin@localhost", "role": "Admin"},
+				{"userId": 2, "login": "terraform", "email": "terraform@example.com", "role": "Admin"}
+			]}`)
+		case r.Method == "GET" && r.URL.Path == "/api/users":
+			fmt.Fprint(w, `[
+				{"id": 1, "login": "admin", "email": "admin@localhost"},
+				{"id": 2, "login": "terraform", "email": "terraform@example.com"}
+			]`)
+		case r.Method == "DELETE":
+			removed = append(removed, r.URL.Path)
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("admin:admin", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both admins were read into the state before they were ignored, one by
+	// its email and the admin_user by its login.
+	state := &terraform.InstanceState{ID: "4", Attributes: map[string]string{
+		"name":       "Ops",
+		"admin_user": "admin",
+		"admins.#":   "2",
+		"admins.0":   "admin@localhost",
+		"admins.1":   "terraform@example.com",
+	}}
+	diff := &terraform.InstanceDiff{Attributes: map[string]*terraform.ResourceAttrDiff{
+		"ignore_users.#": {Old: "0", New: "1"},
+		"ignore_users.0": {Old: "", New: "terraform@example.com"},
+		"admins.#":       {Old: "2", New: "0"},
+		"admins.0":       {Old: "admin@localhost", NewRemoved: true},
+		"admins.1":       {Old: "terraform@example.com", NewRemoved: true},
+	}}
+	d, err := schema.InternalMap(ResourceOrganization().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UpdateUsers(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 0 {
+		t.Errorf("expected the ignored users to be kept, removed %v", removed)
+	}
+}
+
+func TestCustomizeOrganizationDiff_ignoredAndListed(t *testing.T) {
+	cases := []struct {
+		raw map[string]interface{}
+		err string
+	}{
+		{map[string]interface{}{
+			"name":         "Ops",
+			"ignore_users": []interface{}{"terraform@example.com"},
+			"admins":       []interface{}{"jane@example.com"},
+		}, ""},
+		{map[string]interface{}{
+			"name":         "Ops",
+			"ignore_users": []interface{}{"terraform@example.com"},
+			"editors":      []interface{}{"jane@example.com", "terraform@example.com"},
+		}, "User 'terraform@example.com' cannot be both ignored and listed in editors."},
+	}
+	for _, c := range cases {
+		raw, err := config.NewRawConfig(c.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ResourceOrganization().Diff(nil, terraform.NewResourceConfig(raw), nil)
+		if c.err == "" && err != nil {
+			t.Errorf("unexpected error %s", err)
+		}
+		if c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)) {
+			t.Errorf("expected error containing %q, got %v", c.err, err)
+		}
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	var saved orgPreferences
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Grafana-Org-Id"); got != "4" {
+			t.Errorf("expected the request to be made in org 4, got %q", got)
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/dashboards/uid/home":
+			fmt.Fprint(w, `{"dashboard": {"id": 12, "uid": "home"}}`)
+		case r.Method == "PUT" && r.URL.Path == "/api/org/preferences":
+			json.NewDecoder(r.Body).Decode(&saved)
+			fmt.Fprint(w, `{}`)
+		case r.Method == "GET" && r.URL.Path == "/api/org/preferences":
+			json.NewEncoder(w).Encode(saved)
+		case r.Method == "GET" && r.URL.Path == "/api/search":
+			if got := r.URL.Query().Get("dashboardIds"); got != "12" {
+				t.Errorf("expected a search for dashboard 12, got %q", got)
+			}
+			fmt.Fprint(w, `[{"id": 12, "uid": "home"}]`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := newClient("admin:admin", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preferences := map[string]interface{}{
+		"theme":              "dark",
+		"home_dashboard_uid": "home",
+		"timezone":           "utc",
+	}
+	d := schema.TestResourceDataRaw(t, ResourceOrganization().Schema, map[string]interface{}{
+		"name":        "Ops",
+		"preferences": []interface{}{preferences},
+	})
+	d.SetId("4")
+	if err := UpdatePreferences(d, c); err != nil {
+		t.Fatal(err)
+	}
+	expected := orgPreferences{Theme: "dark", HomeDashboardId: 12, Timezone: "utc"}
+	if saved != expected {
+		t.Fatalf("expected preferences %+v to be saved, got %+v", expected, saved)
+	}
+
+	if err := ReadPreferences(d, c); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range preferences {
+		if got := d.Get("preferences.0." + k); got != v {
+			t.Errorf("expected %s to be read as %v, got %v", k, v, got)
+		}
+	}
+}
+
 func testAccOrganizationCheckExists(rn string, a *gapi.Org) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -195,7 +724,7 @@ func testAccOrganizationCheckExists(rn string, a *gapi.Org) resource.TestCheckFu
 			return fmt.Errorf("resource id is malformed")
 		}
 
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		org, err := client.Org(id)
 		if err != nil {
 			return fmt.Errorf("error getting data source: %s", err)
@@ -209,7 +738,7 @@ func testAccOrganizationCheckExists(rn string, a *gapi.Org) resource.TestCheckFu
 
 func testAccOrganizationCheckDestroy(a *gapi.Org) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		client := testAccProvider.Meta().(*gapi.Client)
+		client := testAccProvider.Meta().(*client).gapi
 		org, err := client.Org(a.Id)
 		if err == nil && org.Name != "" {
 			return fmt.Errorf("organization still exists")
@@ -229,6 +758,17 @@ resource "grafana_organization" "test" {
 }
 `
 
+const testAccOrganizationConfig_nameConflict = `
+resource "grafana_organization" "other" {
+    name = "terraform-acc-test-other"
+}
+
+resource "grafana_organization" "test" {
+    name = "terraform-acc-test-other"
+    depends_on = ["grafana_organization.other"]
+}
+`
+
 const testAccOrganizationConfig_usersCreate = `
 resource "grafana_organization" "test" {
     name = "terraform-acc-test"
@@ -278,3 +818,28 @@ resource "grafana_organization" "test" {
     ]
 }
 `
+
+func testAccOrganizationConfig_preferences(theme string) string {
+	return fmt.Sprintf(`
+resource "grafana_organization" "test" {
+    name = "terraform-acc-test-preferences"
+    preferences {
+        theme = "%s"
+        timezone = "utc"
+    }
+}
+`, theme)
+}
+
+func testAccOrganizationConfig_address(city string) string {
+	return fmt.Sprintf(`
+resource "grafana_organization" "test" {
+    name = "terraform-acc-test-address"
+    address {
+        address1 = "Main Street 1"
+        city     = "%s"
+        country  = "Germany"
+    }
+}
+`, city)
+}
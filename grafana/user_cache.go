@@ -0,0 +1,106 @@
+package grafana
+
+import (
+	"sync"
+	"time"
+
+	gapi "github.com/nytm/go-grafana-api"
+)
+
+// userCacheTTL bounds how long a listing of the user directory is reused.
+// It only needs to cover a single apply.
+const userCacheTTL = time.Minute
+
+// userCache memoizes the email to id mapping of Grafana's user directory so
+// that resources reconciling users during the same apply don't each list
+// every user. The directory is global to the Grafana server, so a single
+// cache is shared by all resources of a provider instance.
+type userCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	fetched time.Time
+	ids     map[string]int64
+}
+
+func newUserCache(ttl time.Duration) *userCache {
+	return &userCache{ttl: ttl}
+}
+
+// get returns the email to id mapping, calling list to refresh it when the
+// cache is empty or has expired. Concurrent callers wait for a single refresh.
+// The returned map must not be modified.
+func (c *userCache) get(list func() ([]gapi.User, error)) (map[string]int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ids != nil && time.Since(c.fetched) < c.ttl {
+		return c.ids, nil
+	}
+	users, err := list()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int64, len(users))
+	for _, u := range users {
+		ids[u.Email] = u.Id
+	}
+	c.ids, c.fetched = ids, time.Now()
+	return ids, nil
+}
+
+// invalidate drops the cached mapping, e.g. after a user has been created.
+func (c *userCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ids = nil
+}
+
+// orgUserCache memoizes the user listing of each organization. Org
+// membership changes far more often than the user directory, so entries are
+// kept per org and dropped whenever the provider changes an org's users.
+type orgUserCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[int64]*orgUserCacheEntry
+}
+
+type orgUserCacheEntry struct {
+	mu      sync.Mutex
+	fetched time.Time
+	users   []gapi.OrgUser
+}
+
+func newOrgUserCache(ttl time.Duration) *orgUserCache {
+	return &orgUserCache{ttl: ttl, entries: make(map[int64]*orgUserCacheEntry)}
+}
+
+// get returns the users of an organization, calling list to refresh them when
+// they aren't cached or have expired. Concurrent callers for the same org
+// wait for a single refresh. The returned slice must not be modified.
+func (c *orgUserCache) get(orgId int64, list func(int64) ([]gapi.OrgUser, error)) ([]gapi.OrgUser, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[orgId]
+	if !ok {
+		entry = &orgUserCacheEntry{}
+		c.entries[orgId] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.users != nil && time.Since(entry.fetched) < c.ttl {
+		return entry.users, nil
+	}
+	users, err := list(orgId)
+	if err != nil {
+		return nil, err
+	}
+	entry.users, entry.fetched = users, time.Now()
+	return users, nil
+}
+
+// invalidate drops the cached users of an organization.
+func (c *orgUserCache) invalidate(orgId int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, orgId)
+}
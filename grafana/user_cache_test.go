@@ -0,0 +1,200 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gapi "github.com/nytm/go-grafana-api"
+)
+
+// testUserDirectory serves n users from /api/users and counts the listings.
+func testUserDirectory(t testing.TB, n int, listings *int64) (*gapi.Client, *httptest.Server) {
+	users := make([]gapi.User, n)
+	for i := range users {
+		users[i] = gapi.User{Id: int64(i + 1), Email: fmt.Sprintf("user%d@example.com", i+1)}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(listings, 1)
+		json.NewEncoder(w).Encode(users)
+	}))
+	c, err := gapi.New("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, server
+}
+
+func TestUserCache(t *testing.T) {
+	var listings int64
+	gc, server := testUserDirectory(t, 3, &listings)
+	defer server.Close()
+	cache := newUserCache(time.Minute)
+
+	for i := 0; i < 5; i++ {
+		ids, err := cache.get(gc.Users)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids["user2@example.com"] != 2 {
+			t.Fatalf("expected user2@example.com to have id 2, got %d", ids["user2@example.com"])
+		}
+	}
+	if listings != 1 {
+		t.Fatalf("expected 1 listing, got %d", listings)
+	}
+
+	cache.invalidate()
+	if _, err := cache.get(gc.Users); err != nil {
+		t.Fatal(err)
+	}
+	if listings != 2 {
+		t.Fatalf("expected invalidate to force a new listing, got %d listings", listings)
+	}
+}
+
+func TestUserCache_expired(t *testing.T) {
+	var listings int64
+	gc, server := testUserDirectory(t, 3, &listings)
+	defer server.Close()
+	cache := newUserCache(0)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.get(gc.Users); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if listings != 3 {
+		t.Fatalf("expected every lookup to list users once the ttl expired, got %d listings", listings)
+	}
+}
+
+// The benchmarks below resolve the users of 50 resources against a directory
+// of 1000 users, with and without sharing a cache between them.
+
+func BenchmarkUserLookup_uncached(b *testing.B) {
+	var listings int64
+	gc, server := testUserDirectory(b, 1000, &listings)
+	defer server.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for r := 0; r < 50; r++ {
+			if _, err := newUserCache(userCacheTTL).get(gc.Users); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.Logf("%d listings for %d ops", listings, b.N)
+}
+
+func BenchmarkUserLookup_cached(b *testing.B) {
+	var listings int64
+	gc, server := testUserDirectory(b, 1000, &listings)
+	defer server.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache := newUserCache(userCacheTTL)
+		for r := 0; r < 50; r++ {
+			if _, err := cache.get(gc.Users); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.Logf("%d listings for %d ops", listings, b.N)
+}
+
+// testOrgUserDirectory serves n users in every org from the paginated org
+// users endpoint and counts the requests.
+func testOrgUserDirectory(t testing.TB, n int, requests *int64) (*client, *httptest.Server) {
+	users := make([]gapi.OrgUser, n)
+	for i := range users {
+		users[i] = gapi.OrgUser{UserId: int64(i + 1), Email: fmt.Sprintf("user%d@example.com", i+1), Role: "Viewer"}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(requests, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("perpage"))
+		start, end := (page-1)*perPage, page*perPage
+		if end > len(users) {
+			end = len(users)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"totalCount": len(users),
+			"orgUsers":   users[start:end],
+		})
+	}))
+	c, err := newClient("token", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, server
+}
+
+func TestOrgUserCache(t *testing.T) {
+	var requests int64
+	c, server := testOrgUserDirectory(t, 3, &requests)
+	defer server.Close()
+	cache := newOrgUserCache(time.Minute)
+
+	for i := 0; i < 5; i++ {
+		for _, orgId := range []int64{1, 2} {
+			users, err := cache.get(orgId, c.listOrgUsers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) != 3 {
+				t.Fatalf("expected 3 users, got %d", len(users))
+			}
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("expected 1 request per org, got %d", requests)
+	}
+
+	cache.invalidate(1)
+	for _, orgId := range []int64{1, 2} {
+		if _, err := cache.get(orgId, c.listOrgUsers); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("expected invalidate to only refresh org 1, got %d requests", requests)
+	}
+}
+
+// The benchmarks below read the users of a 5000 user org 20 times, as
+// resources of one apply would, with and without sharing a cache.
+
+func BenchmarkOrgUserLookup_uncached(b *testing.B) {
+	var requests int64
+	c, server := testOrgUserDirectory(b, 5000, &requests)
+	defer server.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for r := 0; r < 20; r++ {
+			if _, err := c.listOrgUsers(1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkOrgUserLookup_cached(b *testing.B) {
+	var requests int64
+	c, server := testOrgUserDirectory(b, 5000, &requests)
+	defer server.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache := newOrgUserCache(userCacheTTL)
+		for r := 0; r < 20; r++ {
+			if _, err := cache.get(1, c.listOrgUsers); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
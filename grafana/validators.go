@@ -0,0 +1,118 @@
+package grafana
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emailPattern is deliberately loose: Grafana accepts any address with a
+// single @ and no whitespace, including ones without a TLD such as the
+// default admin@localhost.
+var emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+$`)
+
+// ValidateEmail checks that a value looks like an email address. Empty values
+// are accepted since every email attribute it's used for is optional.
+func ValidateEmail(emailI interface{}, k string) ([]string, []error) {
+	email := emailI.(string)
+	if email != "" && !emailPattern.MatchString(email) {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid email address", k, email)}
+	}
+	return nil, nil
+}
+
+// ValidateEmailList validates every address of a list separated the way
+// Grafana's email notifier splits them: by semicolons, commas or newlines.
+func ValidateEmailList(emailsI interface{}, k string) ([]string, []error) {
+	var errs []error
+	emails := strings.FieldsFunc(emailsI.(string), func(r rune) bool {
+		return r == ';' || r == ',' || r == '\n'
+	})
+	for _, email := range emails {
+		_, es := ValidateEmail(strings.TrimSpace(email), k)
+		errs = append(errs, es...)
+	}
+	return nil, errs
+}
+
+// ValidateOrgRole checks that a value is one of the roles a user can have in
+// an organization.
+func ValidateOrgRole(roleI interface{}, k string) ([]string, []error) {
+	switch role := roleI.(string); role {
+	case "Admin", "Editor", "Viewer":
+		return nil, nil
+	default:
+		return nil, []error{fmt.Errorf("%s: %q is not a valid role, expected one of Admin, Editor or Viewer", k, role)}
+	}
+}
+
+// domainPattern matches the domain part of an email address, e.g. example.com
+// or localhost.
+var domainPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?$`)
+
+// ValidateEmailDomain checks that a value is a domain emails can be sent to,
+// without the @.
+func ValidateEmailDomain(domainI interface{}, k string) ([]string, []error) {
+	domain := domainI.(string)
+	if !domainPattern.MatchString(domain) {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid email domain, e.g. example.com", k, domain)}
+	}
+	return nil, nil
+}
+
+// uidPattern matches the uids Grafana accepts for dashboards, folders and
+// datasources.
+var uidPattern = regexp.MustCompile(`^[a-zA-Z0-9\-_]{1,40}$`)
+
+// ValidateUID checks that a value can be used as a Grafana uid. Empty values
+// are accepted so that Grafana generates one.
+func ValidateUID(uidI interface{}, k string) ([]string, []error) {
+	uid := uidI.(string)
+	if uid != "" && !uidPattern.MatchString(uid) {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid uid, it must be at most 40 letters, digits, dashes or underscores", k, uid)}
+	}
+	return nil, nil
+}
+
+// intervalPattern matches the intervals Grafana takes for dashboard refresh,
+// e.g. 30s or 1h.
+var intervalPattern = regexp.MustCompile(`^(\d+)(ms|s|m|h|d|w|M|y)$`)
+
+var intervalUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+	"w":  7 * 24 * time.Hour,
+	"M":  30 * 24 * time.Hour,
+	"y":  365 * 24 * time.Hour,
+}
+
+// parseInterval parses an interval the way Grafana writes them.
+func parseInterval(interval string) (time.Duration, error) {
+	m := intervalPattern.FindStringSubmatch(interval)
+	if m == nil {
+		return 0, fmt.Errorf("%q is not a valid interval, e.g. 30s, 5m or 1h", interval)
+	}
+	n, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(n) * intervalUnits[m[2]], nil
+}
+
+// ValidateInterval checks that a value is an interval Grafana understands.
+// Empty values are accepted since every interval attribute is optional.
+func ValidateInterval(intervalI interface{}, k string) ([]string, []error) {
+	interval := intervalI.(string)
+	if interval == "" {
+		return nil, nil
+	}
+	if _, err := parseInterval(interval); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	return nil, nil
+}
@@ -0,0 +1,141 @@
+package grafana
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateEmail(t *testing.T) {
+	cases := []struct {
+		email string
+		valid bool
+	}{
+		{"", true},
+		{"john.doe@example.com", true},
+		{"admin@localhost", true},
+		{"john.doe+grafana@sub.example.com", true},
+		{"john.doe", false},
+		{"john doe@example.com", false},
+		{"john@doe@example.com", false},
+		{"@example.com", false},
+		{"john.doe@", false},
+	}
+	for _, c := range cases {
+		_, errs := ValidateEmail(c.email, "email")
+		if c.valid && len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", c.email, errs)
+		}
+		if !c.valid && len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", c.email)
+		}
+	}
+}
+
+func TestValidateEmailList(t *testing.T) {
+	cases := []struct {
+		emails string
+		errs   int
+	}{
+		{"", 0},
+		{"foo@example.net;bar@example.net", 0},
+		{"foo@example.net, bar@example.net\nbaz@example.net;", 0},
+		{"foo@example.net;bar", 1},
+		{"foo;bar", 2},
+	}
+	for _, c := range cases {
+		if _, errs := ValidateEmailList(c.emails, "addresses"); len(errs) != c.errs {
+			t.Errorf("expected %d errors for %q, got %v", c.errs, c.emails, errs)
+		}
+	}
+}
+
+func TestValidateEmailDomain(t *testing.T) {
+	cases := []struct {
+		domain string
+		valid  bool
+	}{
+		{"example.com", true},
+		{"sub.example-corp.io", true},
+		{"localhost", true},
+		{"", false},
+		{"@example.com", false},
+		{".example.com", false},
+		{"example com", false},
+	}
+	for _, c := range cases {
+		_, errs := ValidateEmailDomain(c.domain, "allowed_email_domains.0")
+		if c.valid && len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", c.domain, errs)
+		}
+		if !c.valid && len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", c.domain)
+		}
+	}
+}
+
+func TestValidateOrgRole(t *testing.T) {
+	for _, role := range []string{"Admin", "Editor", "Viewer"} {
+		if _, errs := ValidateOrgRole(role, "role"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", role, errs)
+		}
+	}
+	for _, role := range []string{"", "admin", "Owner"} {
+		if _, errs := ValidateOrgRole(role, "role"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", role)
+		}
+	}
+}
+
+func TestValidateUID(t *testing.T) {
+	cases := []struct {
+		uid   string
+		valid bool
+	}{
+		{"", true},
+		{"prometheus", true},
+		{"P1809F7CD0C75ACF3", true},
+		{"my_data-source", true},
+		{"0123456789012345678901234567890123456789", true},
+		{"01234567890123456789012345678901234567890", false},
+		{"my data source", false},
+		{"prometheus/eu", false},
+	}
+	for _, c := range cases {
+		_, errs := ValidateUID(c.uid, "uid")
+		if c.valid && len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", c.uid, errs)
+		}
+		if !c.valid && len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", c.uid)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	cases := []struct {
+		interval string
+		expected time.Duration
+		valid    bool
+	}{
+		{"500ms", 500 * time.Millisecond, true},
+		{"30s", 30 * time.Second, true},
+		{"5m", 5 * time.Minute, true},
+		{"1h", time.Hour, true},
+		{"1d", 24 * time.Hour, true},
+		{"2w", 14 * 24 * time.Hour, true},
+		{"", 0, false},
+		{"5", 0, false},
+		{"1.5m", 0, false},
+		{"5 m", 0, false},
+		{"5x", 0, false},
+	}
+	for _, c := range cases {
+		got, err := parseInterval(c.interval)
+		if c.valid && (err != nil || got != c.expected) {
+			t.Errorf("expected %q to parse to %s, got %s (%v)", c.interval, c.expected, got, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("expected %q to be invalid", c.interval)
+		}
+	}
+}